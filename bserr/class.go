@@ -0,0 +1,116 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bserr
+
+// Class is a set of flags that classify an error.
+// It allows to decide how to handle the error (i.e. retry, report, alert)
+// without hard-coding the lists of codes.
+type Class uint8
+
+const (
+	// Retryable is set when the failed operation could succeed if retried,
+	// i.e. after a transaction serialization failure.
+	Retryable Class = 1 << iota
+
+	// Transient is set when the failure is temporary,
+	// i.e. a lost connection or a lock timeout.
+	Transient
+
+	// ClientCaused is set when the error is caused by the request itself,
+	// i.e. a constraint violation or a syntax error.
+	ClientCaused
+
+	// ServerCaused is set when the error is caused by the database server,
+	// i.e. it is out of memory or an internal error occurred.
+	ServerCaused
+
+	// SafeToRetry is set when the failed operation is known to have had no effect,
+	// so that it could be retried even if it is not idempotent.
+	SafeToRetry
+)
+
+// Has reports whether all the given flags are set in the class.
+func (c Class) Has(flags Class) bool {
+	return c&flags == flags
+}
+
+// Class returns the default classification of the code.
+// The drivers may refine the classification of a specific error,
+// see the Error.Class.
+func (i Code) Class() Class {
+	switch i {
+	case ConcurrentUpdate, Deadlock, LockNotAvailable:
+		return Retryable | Transient | SafeToRetry
+	case TooManyConnections:
+		return Retryable | Transient | ServerCaused | SafeToRetry
+	case ConnectionLost:
+		return Retryable | Transient | ServerCaused
+	case Timeout:
+		return Retryable | Transient
+	case Canceled:
+		return Transient
+	case OutOfMemory:
+		return Transient | ServerCaused
+	case OutOfDisk, InternalError:
+		return ServerCaused
+	case NotFound, UniqueViolation, TableNotFound, DataException, AuthenticationFailed,
+		ForeignKeyViolation, ConstraintViolation, InvalidInputSyntax, PermissionDenied,
+		TxDone, ReadOnlyTransaction, NotNullViolation, CheckViolation, ExclusionViolation,
+		NumericOverflow, StringTooLong, InvalidTextRepresentation:
+		return ClientCaused
+	}
+	return 0
+}
+
+// ClassOf returns the classification of the given error.
+// If the error wraps an Error, its Class is returned, or if the Class
+// is not set, the class of its Code. Otherwise, it returns zero.
+func ClassOf(err error) Class {
+	e, ok := As(err)
+	if !ok {
+		return 0
+	}
+	if e.Class != 0 {
+		return e.Class
+	}
+	return e.Code.Class()
+}
+
+// IsRetryable reports whether the operation that failed with the error
+// could succeed if retried.
+func IsRetryable(err error) bool {
+	return ClassOf(err).Has(Retryable)
+}
+
+// IsTransient reports whether the error is a temporary failure.
+func IsTransient(err error) bool {
+	return ClassOf(err).Has(Transient)
+}
+
+// IsClientCaused reports whether the error is caused by the request itself.
+func IsClientCaused(err error) bool {
+	return ClassOf(err).Has(ClientCaused)
+}
+
+// IsServerCaused reports whether the error is caused by the database server.
+func IsServerCaused(err error) bool {
+	return ClassOf(err).Has(ServerCaused)
+}
+
+// IsSafeToRetry reports whether the operation that failed with the error
+// could be retried, even if it is not idempotent.
+func IsSafeToRetry(err error) bool {
+	return ClassOf(err).Has(Retryable | SafeToRetry)
+}
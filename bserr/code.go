@@ -14,6 +14,8 @@
 
 package bserr
 
+//go:generate enumer -type=Code -output=code_string.go code.go
+
 // Code is a sql driver independent error code, used to determine the type of the error.
 type Code uint32
 
@@ -72,4 +74,71 @@ const (
 
 	// Unknown is returned when the error is unknown.
 	Unknown Code = 17
+
+	// Deadlock is returned when a deadlock between transactions is detected.
+	// Its parent code is the ConcurrentUpdate.
+	Deadlock Code = 18
+
+	// LockNotAvailable is returned when a lock could not be acquired,
+	// either immediately (NOWAIT) or within the lock timeout.
+	// Its parent code is the ConcurrentUpdate.
+	LockNotAvailable Code = 19
+
+	// Canceled is returned when the query was canceled, i.e. by the user
+	// or due to the statement timeout.
+	// Its parent code is the Timeout.
+	Canceled Code = 20
+
+	// ConnectionLost is returned when the connection to the database
+	// could not be established or was lost.
+	ConnectionLost Code = 21
+
+	// ReadOnlyTransaction is returned when a write is attempted
+	// in a read-only transaction or on a read-only server.
+	// Its parent code is the PermissionDenied.
+	ReadOnlyTransaction Code = 22
+
+	// NotNullViolation is returned when a not null constraint is violated.
+	// Its parent code is the ConstraintViolation.
+	NotNullViolation Code = 23
+
+	// CheckViolation is returned when a check constraint is violated.
+	// Its parent code is the ConstraintViolation.
+	CheckViolation Code = 24
+
+	// ExclusionViolation is returned when an exclusion constraint is violated.
+	// Its parent code is the ConstraintViolation.
+	ExclusionViolation Code = 25
+
+	// NumericOverflow is returned when a numeric value is out of range.
+	// Its parent code is the DataException.
+	NumericOverflow Code = 26
+
+	// StringTooLong is returned when a string value is too long for its column.
+	// Its parent code is the DataException.
+	StringTooLong Code = 27
+
+	// InvalidTextRepresentation is returned when a value could not be parsed
+	// into the column type, i.e. a non-numeric string into an integer.
+	// Its parent code is the DataException.
+	InvalidTextRepresentation Code = 28
 )
+
+// Parent returns the coarse code that the code refines,
+// i.e. the ConstraintViolation for the NotNullViolation.
+// If the code doesn't refine any other code, the code itself is returned.
+func (i Code) Parent() Code {
+	switch i {
+	case Deadlock, LockNotAvailable:
+		return ConcurrentUpdate
+	case Canceled:
+		return Timeout
+	case ReadOnlyTransaction:
+		return PermissionDenied
+	case NotNullViolation, CheckViolation, ExclusionViolation:
+		return ConstraintViolation
+	case NumericOverflow, StringTooLong, InvalidTextRepresentation:
+		return DataException
+	}
+	return i
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bserr
+
+import (
+	"errors"
+)
+
+// Error is a sql driver independent error.
+// It wraps the original driver error and holds its code and details.
+// The details are filled only if the driver supports them.
+//
+// The Error matches its Code with errors.Is, i.e.:
+//
+//	if errors.Is(err, bserr.UniqueViolation) {
+//		// handle unique violation
+//	}
+type Error struct {
+	// Code is the driver independent error code.
+	Code Code
+
+	// SQLState is the SQLSTATE code of the error, i.e. "23505".
+	SQLState string
+
+	// Number is the native error number, i.e. the MySQL error number 1062.
+	Number int
+
+	// Schema is the name of the schema related to the error.
+	Schema string
+
+	// Table is the name of the table related to the error.
+	Table string
+
+	// Column is the name of the column related to the error.
+	Column string
+
+	// Constraint is the name of the constraint related to the error.
+	Constraint string
+
+	// DataType is the name of the data type related to the error.
+	DataType string
+
+	// Detail is the secondary error message, carrying more detail about the problem.
+	Detail string
+
+	// Hint is the optional suggestion what to do about the problem.
+	Hint string
+
+	// Class is the classification of the error, possibly refined by the driver.
+	// If it is zero, the class of the Code applies.
+	Class Class
+
+	// Driver is the name of the driver that returned the error.
+	Driver string
+
+	// Err is the original driver error.
+	Err error
+}
+
+// Error implements the error interface.
+// It returns the message of the original error.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Code.String()
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the original driver error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the Code of the error or its parent code,
+// i.e. an error with the NotNullViolation code matches the ConstraintViolation.
+func (e *Error) Is(target error) bool {
+	c, ok := target.(Code)
+	return ok && (c == e.Code || c == e.Code.Parent())
+}
+
+// Error implements the error interface, so that the Code could be used
+// as the target of the errors.Is function.
+func (i Code) Error() string {
+	return i.String()
+}
+
+// As finds the first Error in the chain of the given error.
+func As(err error) (*Error, bool) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return nil, false
+	}
+	return e, true
+}
+
+// CodeOf returns the Code of the first Error in the chain of the given error.
+// If the err is nil it returns OK, and if it doesn't wrap an Error it returns Unknown.
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+	if e, ok := As(err); ok {
+		return e.Code
+	}
+	return Unknown
+}
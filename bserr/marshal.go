@@ -0,0 +1,88 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bserr
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+// Compile time check if Code implements the marshalling interfaces and flag.Value.
+var (
+	_ encoding.TextMarshaler   = Code(0)
+	_ encoding.TextUnmarshaler = (*Code)(nil)
+	_ json.Marshaler           = Code(0)
+	_ json.Unmarshaler         = (*Code)(nil)
+	_ flag.Value               = (*Code)(nil)
+)
+
+// ParseCode parses the code from its name, i.e. "UniqueViolation".
+// The name is case-insensitive, and the unknown names are rejected.
+func ParseCode(name string) (Code, error) {
+	c, err := CodeString(name)
+	if err != nil {
+		return 0, fmt.Errorf("bserr: unknown code name %q", name)
+	}
+	return c, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The codes that are not defined in this package are rejected,
+// so that the marshalled text could always be parsed back.
+func (i Code) MarshalText() ([]byte, error) {
+	if !i.IsACode() {
+		return nil, fmt.Errorf("bserr: invalid code %d", int(i))
+	}
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The receiver is left unchanged if the text is not a code name.
+func (i *Code) UnmarshalText(text []byte) error {
+	c, err := ParseCode(string(text))
+	if err != nil {
+		return err
+	}
+	*i = c
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The code is marshalled as a JSON string of its name.
+func (i Code) MarshalJSON() ([]byte, error) {
+	text, err := i.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The receiver is left unchanged if the data is not a JSON string of a code name.
+func (i *Code) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("bserr: code should be a JSON string, got %s", data)
+	}
+	return i.UnmarshalText([]byte(s))
+}
+
+// Set implements flag.Value.
+// The receiver is left unchanged if the value is not a code name.
+func (i *Code) Set(value string) error {
+	return i.UnmarshalText([]byte(value))
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bserr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCodeJSON(t *testing.T) {
+	data, err := json.Marshal(UniqueViolation)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `"UniqueViolation"` {
+		t.Errorf("json.Marshal() = %s, want %q", data, "UniqueViolation")
+	}
+
+	var c Code
+	if err = json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if c != UniqueViolation {
+		t.Errorf("json.Unmarshal() = %v, want %v", c, UniqueViolation)
+	}
+
+	if _, err = json.Marshal(Code(99)); err == nil {
+		t.Error("json.Marshal(Code(99)) error = nil, want error")
+	}
+}
+
+func TestCodeUnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		unmarshal func(c *Code) error
+	}{
+		{name: "text", unmarshal: func(c *Code) error { return c.UnmarshalText([]byte("Bogus")) }},
+		{name: "json", unmarshal: func(c *Code) error { return c.UnmarshalJSON([]byte(`"Bogus"`)) }},
+		{name: "json number", unmarshal: func(c *Code) error { return c.UnmarshalJSON([]byte(`1`)) }},
+		{name: "flag", unmarshal: func(c *Code) error { return c.Set("Bogus") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Timeout
+			if err := tt.unmarshal(&c); err == nil {
+				t.Fatal("error = nil, want error")
+			}
+			if c != Timeout {
+				t.Errorf("code = %v, want unchanged %v", c, Timeout)
+			}
+		})
+	}
+}
+
+func TestCodeSet(t *testing.T) {
+	var c Code
+	if err := c.Set("notfound"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if c != NotFound {
+		t.Errorf("Set() = %v, want %v", c, NotFound)
+	}
+}
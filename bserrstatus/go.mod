@@ -0,0 +1,29 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+module github.com/blockysource/blockysql/bserrstatus
+
+go 1.20
+
+replace github.com/blockysource/blockysql => ../
+
+require (
+	github.com/blockysource/blockysql v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.57.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	google.golang.org/protobuf v1.30.0 // indirect
+)
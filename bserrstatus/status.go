@@ -0,0 +1,179 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bserrstatus maps the bserr codes into the gRPC and HTTP status codes.
+package bserrstatus
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// Status is the status of a bserr.Code in the gRPC and HTTP status spaces.
+type Status struct {
+	// GRPC is the gRPC status code.
+	GRPC codes.Code
+
+	// HTTP is the HTTP status code.
+	HTTP int
+
+	// Message is a public message, safe to be returned to the client.
+	// It never contains database details such as table or constraint names.
+	Message string
+}
+
+// Mapping maps the bserr codes into their Status.
+// The mapping could be overridden by modifying a copy
+// of the DefaultMapping, i.e.:
+//
+//	m := bserrstatus.DefaultMapping()
+//	m[bserr.ForeignKeyViolation] = bserrstatus.Status{
+//		GRPC:    codes.NotFound,
+//		HTTP:    http.StatusNotFound,
+//		Message: "referenced resource not found",
+//	}
+type Mapping map[bserr.Code]Status
+
+// unknownStatus is the status of the codes that are not found in the mapping.
+var unknownStatus = Status{GRPC: codes.Unknown, HTTP: http.StatusInternalServerError, Message: "unknown error"}
+
+// classStatuses are the statuses of the error classes, used for the codes
+// that are not found in the mapping, in the order of precedence.
+var classStatuses = []struct {
+	class  bserr.Class
+	status Status
+}{
+	{class: bserr.Transient, status: Status{GRPC: codes.Unavailable, HTTP: http.StatusServiceUnavailable, Message: "service unavailable"}},
+	{class: bserr.ServerCaused, status: Status{GRPC: codes.Internal, HTTP: http.StatusInternalServerError, Message: "internal error"}},
+	{class: bserr.ClientCaused, status: Status{GRPC: codes.InvalidArgument, HTTP: http.StatusBadRequest, Message: "invalid argument"}},
+}
+
+// defaultMapping is the canonical mapping of the bserr codes.
+var defaultMapping = Mapping{
+	bserr.OK:                        {GRPC: codes.OK, HTTP: http.StatusOK},
+	bserr.NotFound:                  {GRPC: codes.NotFound, HTTP: http.StatusNotFound, Message: "not found"},
+	bserr.UniqueViolation:           {GRPC: codes.AlreadyExists, HTTP: http.StatusConflict, Message: "already exists"},
+	bserr.TableNotFound:             {GRPC: codes.Internal, HTTP: http.StatusInternalServerError, Message: "internal error"},
+	bserr.DataException:             {GRPC: codes.InvalidArgument, HTTP: http.StatusBadRequest, Message: "invalid argument"},
+	bserr.ConcurrentUpdate:          {GRPC: codes.Aborted, HTTP: http.StatusConflict, Message: "concurrent update, try again"},
+	bserr.AuthenticationFailed:      {GRPC: codes.Internal, HTTP: http.StatusInternalServerError, Message: "internal error"},
+	bserr.InternalError:             {GRPC: codes.Internal, HTTP: http.StatusInternalServerError, Message: "internal error"},
+	bserr.ForeignKeyViolation:       {GRPC: codes.FailedPrecondition, HTTP: http.StatusConflict, Message: "referenced resource conflict"},
+	bserr.ConstraintViolation:       {GRPC: codes.InvalidArgument, HTTP: http.StatusBadRequest, Message: "invalid argument"},
+	bserr.InvalidInputSyntax:        {GRPC: codes.Internal, HTTP: http.StatusInternalServerError, Message: "internal error"},
+	bserr.PermissionDenied:          {GRPC: codes.PermissionDenied, HTTP: http.StatusForbidden, Message: "permission denied"},
+	bserr.OutOfDisk:                 {GRPC: codes.ResourceExhausted, HTTP: http.StatusServiceUnavailable, Message: "service unavailable"},
+	bserr.OutOfMemory:               {GRPC: codes.ResourceExhausted, HTTP: http.StatusServiceUnavailable, Message: "service unavailable"},
+	bserr.TooManyConnections:        {GRPC: codes.Unavailable, HTTP: http.StatusServiceUnavailable, Message: "service unavailable"},
+	bserr.TxDone:                    {GRPC: codes.Internal, HTTP: http.StatusInternalServerError, Message: "internal error"},
+	bserr.Timeout:                   {GRPC: codes.DeadlineExceeded, HTTP: http.StatusGatewayTimeout, Message: "deadline exceeded"},
+	bserr.Unknown:                   unknownStatus,
+	bserr.Deadlock:                  {GRPC: codes.Aborted, HTTP: http.StatusConflict, Message: "concurrent update, try again"},
+	bserr.LockNotAvailable:          {GRPC: codes.Aborted, HTTP: http.StatusConflict, Message: "concurrent update, try again"},
+	bserr.Canceled:                  {GRPC: codes.Canceled, HTTP: 499, Message: "request canceled"}, // 499 Client Closed Request.
+	bserr.ConnectionLost:            {GRPC: codes.Unavailable, HTTP: http.StatusServiceUnavailable, Message: "service unavailable"},
+	bserr.ReadOnlyTransaction:       {GRPC: codes.PermissionDenied, HTTP: http.StatusForbidden, Message: "permission denied"},
+	bserr.NotNullViolation:          {GRPC: codes.InvalidArgument, HTTP: http.StatusBadRequest, Message: "missing required value"},
+	bserr.CheckViolation:            {GRPC: codes.InvalidArgument, HTTP: http.StatusBadRequest, Message: "invalid argument"},
+	bserr.ExclusionViolation:        {GRPC: codes.AlreadyExists, HTTP: http.StatusConflict, Message: "conflicts with an existing resource"},
+	bserr.NumericOverflow:           {GRPC: codes.OutOfRange, HTTP: http.StatusBadRequest, Message: "value out of range"},
+	bserr.StringTooLong:             {GRPC: codes.InvalidArgument, HTTP: http.StatusBadRequest, Message: "value too long"},
+	bserr.InvalidTextRepresentation: {GRPC: codes.InvalidArgument, HTTP: http.StatusBadRequest, Message: "invalid value format"},
+}
+
+// DefaultMapping returns a copy of the canonical mapping of the bserr codes.
+func DefaultMapping() Mapping {
+	m := make(Mapping, len(defaultMapping))
+	for code, s := range defaultMapping {
+		m[code] = s
+	}
+	return m
+}
+
+// Status returns the status of the given code.
+// If the code is not found in the mapping, the status of its parent code
+// is returned, then the status of its bserr.Class, i.e. 503 Service Unavailable
+// for the transient errors, and then the status of the bserr.Unknown.
+func (m Mapping) Status(code bserr.Code) Status {
+	return m.status(code, code.Class())
+}
+
+// status returns the status of the given code with the given class.
+// The bserr.Unknown is not considered a code-specific entry,
+// so that the unknown errors with a known class get the status of the class.
+func (m Mapping) status(code bserr.Code, class bserr.Class) Status {
+	if code != bserr.Unknown {
+		if s, ok := m[code]; ok {
+			return s
+		}
+		if s, ok := m[code.Parent()]; ok {
+			return s
+		}
+	}
+	for _, cs := range classStatuses {
+		if class.Has(cs.class) {
+			return cs.status
+		}
+	}
+	if s, ok := m[bserr.Unknown]; ok {
+		return s
+	}
+	return unknownStatus
+}
+
+// StatusOf returns the status of the given error.
+// The error is expected to wrap a *bserr.Error. Otherwise, the status
+// of the driver independent errors, such as the sql.ErrNoRows or
+// context.DeadlineExceeded is returned, or the status of the bserr.Unknown.
+// The class of the error, possibly refined by the driver, is used
+// when its code is not found in the mapping.
+func (m Mapping) StatusOf(err error) Status {
+	if _, ok := bserr.As(err); !ok {
+		if code, ok := driver.SentinelErrorCode(err); ok {
+			return m.Status(code)
+		}
+	}
+	return m.status(bserr.CodeOf(err), bserr.ClassOf(err))
+}
+
+// CodeStatus returns the status of the given code in the default mapping.
+func CodeStatus(code bserr.Code) Status {
+	return defaultMapping.Status(code)
+}
+
+// StatusOf returns the status of the given error in the default mapping.
+// See the Mapping.StatusOf for details.
+func StatusOf(err error) Status {
+	return defaultMapping.StatusOf(err)
+}
+
+// GRPCCode returns the gRPC status code of the given error in the default mapping.
+func GRPCCode(err error) codes.Code {
+	return defaultMapping.StatusOf(err).GRPC
+}
+
+// HTTPStatus returns the HTTP status code of the given error in the default mapping.
+func HTTPStatus(err error) int {
+	return defaultMapping.StatusOf(err).HTTP
+}
+
+// PublicMessage returns the message of the given error in the default mapping,
+// that is safe to be returned to the client.
+func PublicMessage(err error) string {
+	return defaultMapping.StatusOf(err).Message
+}
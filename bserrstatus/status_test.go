@@ -0,0 +1,74 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bserrstatus
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/blockysource/blockysql/bserr"
+)
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "translated", err: &bserr.Error{Code: bserr.UniqueViolation}, want: http.StatusConflict},
+		{name: "wrapped translated", err: fmt.Errorf("create: %w", &bserr.Error{Code: bserr.NotFound}), want: http.StatusNotFound},
+		{name: "wrapped no rows", err: fmt.Errorf("load: %w", sql.ErrNoRows), want: http.StatusNotFound},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: http.StatusGatewayTimeout},
+		{name: "unknown", err: errors.New("boom"), want: http.StatusInternalServerError},
+		{name: "read only transaction", err: &bserr.Error{Code: bserr.ReadOnlyTransaction}, want: http.StatusForbidden},
+		{name: "unknown transient", err: &bserr.Error{Code: bserr.Unknown, Class: bserr.Transient}, want: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPStatus(tt.err); got != tt.want {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappingStatusClassFallback(t *testing.T) {
+	m := DefaultMapping()
+	delete(m, bserr.ConnectionLost)
+	delete(m, bserr.PermissionDenied)
+	delete(m, bserr.ReadOnlyTransaction)
+
+	tests := []struct {
+		name string
+		code bserr.Code
+		want int
+	}{
+		{name: "code entry", code: bserr.Deadlock, want: http.StatusConflict},
+		{name: "transient", code: bserr.ConnectionLost, want: http.StatusServiceUnavailable},
+		{name: "client caused", code: bserr.ReadOnlyTransaction, want: http.StatusBadRequest},
+		{name: "unknown", code: bserr.Unknown, want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Status(tt.code).HTTP; got != tt.want {
+				t.Errorf("Status(%v).HTTP = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// Conn is a driver specific wrapper over the database/sql.Conn.
+// It provides the same error inspection methods as the DB
+// that opened the connection.
+type Conn struct {
+	db   *DB
+	conn *sql.Conn
+}
+
+// DriverName returns the name of the driver.
+func (c *Conn) DriverName() string {
+	return c.db.DriverName()
+}
+
+// Dialect returns the dialect of the database connection.
+func (c *Conn) Dialect() string {
+	return c.db.Dialect()
+}
+
+// Capabilities returns the features supported by the connected database server.
+func (c *Conn) Capabilities() driver.Capabilities {
+	return c.db.Capabilities()
+}
+
+// ErrorCode returns the error code of the given error
+// if the driver supports it.
+func (c *Conn) ErrorCode(err error) bserr.Code {
+	return c.db.ErrorCode(err)
+}
+
+// HasErrorDetails returns true if the driver supports error details,
+// such as column, table and constraint name.
+func (c *Conn) HasErrorDetails() bool {
+	return c.db.HasErrorDetails()
+}
+
+// ErrorColumn returns the column name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (c *Conn) ErrorColumn(err error) string {
+	return c.db.ErrorColumn(err)
+}
+
+// ErrorTable returns the table name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (c *Conn) ErrorTable(err error) string {
+	return c.db.ErrorTable(err)
+}
+
+// ErrorConstraint returns the constraint name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (c *Conn) ErrorConstraint(err error) string {
+	return c.db.ErrorConstraint(err)
+}
+
+// ErrorClass returns the classification of the given error.
+func (c *Conn) ErrorClass(err error) bserr.Class {
+	return c.db.ErrorClass(err)
+}
+
+// ErrorDetails returns the details of the given error.
+// It returns false if the err is nil or the details are not available.
+func (c *Conn) ErrorDetails(err error) (driver.ErrorDetails, bool) {
+	return c.db.ErrorDetails(err)
+}
+
+// TranslateError translates the given driver error into the *bserr.Error,
+// that carries its code and details, and wraps the original error.
+func (c *Conn) TranslateError(err error) error {
+	return c.db.TranslateError(err)
+}
+
+// Conn returns the underlying database/sql.Conn.
+func (c *Conn) Conn() *sql.Conn {
+	return c.conn
+}
+
+// BeginTx starts a transaction on the connection with the provided options.
+func (c *Conn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := c.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, c.db.wrapErr(err)
+	}
+	return &Tx{db: c.db, tx: tx}, nil
+}
+
+// Close returns the connection to the connection pool.
+func (c *Conn) Close() error {
+	return c.db.wrapErr(c.conn.Close())
+}
+
+// ExecContext executes a query without returning any rows.
+// The args are for any placeholder parameters in the query.
+func (c *Conn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := c.conn.ExecContext(ctx, c.db.rebindQuery(query), args...)
+	return res, c.db.wrapErr(err)
+}
+
+// PingContext verifies the connection to the database is still alive.
+func (c *Conn) PingContext(ctx context.Context) error {
+	return c.db.wrapErr(c.conn.PingContext(ctx))
+}
+
+// PrepareContext creates a prepared statement for later queries or executions.
+// The caller must call the statement's Close method when the statement is no longer needed.
+func (c *Conn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	stmt, err := c.conn.PrepareContext(ctx, c.db.rebindQuery(query))
+	return stmt, c.db.wrapErr(err)
+}
+
+// QueryContext executes a query that returns rows, typically a SELECT.
+// The args are for any placeholder parameters in the query.
+func (c *Conn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := c.conn.QueryContext(ctx, c.db.rebindQuery(query), args...)
+	return rows, c.db.wrapErr(err)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row.
+// QueryRowContext always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called, use the WrapRow to translate them.
+func (c *Conn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return c.conn.QueryRowContext(ctx, c.db.rebindQuery(query), args...)
+}
+
+// Raw executes f exposing the underlying driver connection for the
+// duration of f. The driverConn must not be used outside of f.
+func (c *Conn) Raw(f func(driverConn any) error) error {
+	return c.db.wrapErr(c.conn.Raw(f))
+}
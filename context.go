@@ -0,0 +1,67 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+)
+
+// txKey is the context key of the transaction started by the DB.
+type txKey struct {
+	db *DB
+}
+
+// txState is the state of the transaction stored in the context.
+type txState struct {
+	tx *Tx
+
+	// savepoints is the number of savepoints created in the transaction,
+	// used to generate unique savepoint names.
+	savepoints int
+}
+
+// txState returns the state of the transaction started by this DB
+// and stored in the context.
+func (d *DB) txState(ctx context.Context) (*txState, bool) {
+	ts, ok := ctx.Value(txKey{db: d}).(*txState)
+	return ts, ok
+}
+
+// withTxState returns a copy of the context carrying the transaction state.
+func (d *DB) withTxState(ctx context.Context, ts *txState) context.Context {
+	return context.WithValue(ctx, txKey{db: d}, ts)
+}
+
+// TxFromContext returns the transaction started by the RunInTransaction
+// of this DB, if the context carries one.
+func (d *DB) TxFromContext(ctx context.Context) (*Tx, bool) {
+	ts, ok := d.txState(ctx)
+	if !ok {
+		return nil, false
+	}
+	return ts.tx, true
+}
+
+// From returns the Querier that should be used with the given context.
+// If the context carries a transaction started by the RunInTransaction
+// of this DB, the transaction is returned, otherwise it is the DB itself.
+// This allows the service layer to span multiple repository calls
+// in a single transaction without passing the transaction explicitly.
+func (d *DB) From(ctx context.Context) Querier {
+	if tx, ok := d.TxFromContext(ctx); ok {
+		return tx
+	}
+	return d
+}
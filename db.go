@@ -29,6 +29,14 @@ import (
 type DB struct {
 	driver driver.DB
 	db     *sql.DB
+
+	// translateErrors is true if the errors should be translated
+	// into the *bserr.Error.
+	translateErrors bool
+
+	// rebind is true if the question mark placeholders of the queries
+	// should be converted into the placeholder style of the database.
+	rebind bool
 }
 
 // NewDB creates a new instance of DB.
@@ -36,7 +44,8 @@ var NewDB = newDB
 
 // newDB creates a new instance of DB.
 // The driver is used to determine the error code of the given error.
-func newDB(d driver.DB) (*DB, error) {
+// The options are applied to the DB in the given order.
+func newDB(d driver.DB, opts ...Option) (*DB, error) {
 	if d == nil {
 		return nil, errors.New("blockysql: driver is nil")
 	}
@@ -45,10 +54,14 @@ func newDB(d driver.DB) (*DB, error) {
 		return nil, errors.New("blockysql: driver.DB() is nil")
 	}
 
-	return &DB{
+	db := &DB{
 		driver: d,
 		db:     d.DB(),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(db)
+	}
+	return db, nil
 }
 
 // DriverName returns the name of the driver.
@@ -63,9 +76,44 @@ func (d *DB) Dialect() string {
 	return d.driver.Dialect()
 }
 
+// ServerInfo returns the information about the connected database server,
+// such as its version and flavor within the dialect.
+// If the driver doesn't implement the driver.ServerInformer,
+// an empty ServerInfo is returned.
+func (d *DB) ServerInfo() driver.ServerInfo {
+	if si, ok := d.driver.(driver.ServerInformer); ok {
+		return si.ServerInfo()
+	}
+	return driver.ServerInfo{}
+}
+
+// Capabilities returns the features supported by the connected database server,
+// such as the RETURNING clause or the placeholder style.
+// If the driver doesn't implement the driver.CapabilityDescriber,
+// the driver.DefaultCapabilities of its dialect and server are returned.
+func (d *DB) Capabilities() driver.Capabilities {
+	if cd, ok := d.driver.(driver.CapabilityDescriber); ok {
+		return cd.Capabilities()
+	}
+	return driver.DefaultCapabilities(d.Dialect(), d.ServerInfo())
+}
+
+// ServerVersion returns the parsed version of the connected database server.
+// If the driver doesn't provide it, a zero ServerVersion is returned.
+func (d *DB) ServerVersion() driver.ServerVersion {
+	return d.ServerInfo().ServerVersion
+}
+
 // ErrorCode returns the error code of the given error
 // if the driver supports it.
+// If the error is already translated, its code is returned.
 func (d *DB) ErrorCode(err error) bserr.Code {
+	if e, ok := bserr.As(err); ok {
+		return e.Code
+	}
+	if code, ok := driver.SentinelErrorCode(err); ok {
+		return code
+	}
 	return d.driver.ErrorCode(err)
 }
 
@@ -93,30 +141,113 @@ func (d *DB) ErrorConstraint(err error) string {
 	return d.driver.ErrorConstraint(err)
 }
 
+// ErrorClass returns the classification of the given error.
+// If the driver doesn't implement the driver.ErrorClassifier,
+// the class of the ErrorCode is returned.
+func (d *DB) ErrorClass(err error) bserr.Class {
+	if err == nil {
+		return 0
+	}
+	if _, ok := bserr.As(err); ok {
+		return bserr.ClassOf(err)
+	}
+	if ec, ok := d.driver.(driver.ErrorClassifier); ok {
+		return ec.ErrorClass(err)
+	}
+	return d.ErrorCode(err).Class()
+}
+
+// ErrorDetails returns the details of the given error.
+// If the driver doesn't implement the driver.ErrorDetailer, only the column,
+// table and constraint names are provided.
+// It returns false if the err is nil or the details are not available.
+func (d *DB) ErrorDetails(err error) (driver.ErrorDetails, bool) {
+	if err == nil {
+		return driver.ErrorDetails{}, false
+	}
+
+	if ed, ok := d.driver.(driver.ErrorDetailer); ok {
+		return ed.ErrorDetails(err)
+	}
+
+	if !d.driver.HasErrorDetails() {
+		return driver.ErrorDetails{}, false
+	}
+
+	return driver.ErrorDetails{
+		Table:      d.driver.ErrorTable(err),
+		Column:     d.driver.ErrorColumn(err),
+		Constraint: d.driver.ErrorConstraint(err),
+	}, true
+}
+
+// TranslateError translates the given driver error into the *bserr.Error,
+// that carries its code and details, and wraps the original error.
+// If the err is nil it returns nil, and if it already wraps
+// a *bserr.Error it is returned unchanged.
+func (d *DB) TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := bserr.As(err); ok {
+		return err
+	}
+
+	details, _ := d.ErrorDetails(err)
+	return &bserr.Error{
+		Code:       d.ErrorCode(err),
+		SQLState:   details.SQLState,
+		Number:     details.Number,
+		Schema:     details.Schema,
+		Table:      details.Table,
+		Column:     details.Column,
+		Constraint: details.Constraint,
+		DataType:   details.DataType,
+		Detail:     details.Detail,
+		Hint:       details.Hint,
+		Class:      d.ErrorClass(err),
+		Driver:     d.driver.DriverName(),
+		Err:        err,
+	}
+}
+
 // DB returns the underlying database/sql.DB.
 func (d *DB) DB() *sql.DB {
 	return d.db
 }
 
 // Begin starts a transaction.
-func (d *DB) Begin() (*sql.Tx, error) {
-	return d.db.Begin()
+func (d *DB) Begin() (*Tx, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, d.wrapErr(err)
+	}
+	return &Tx{db: d, tx: tx}, nil
 }
 
 // BeginTx starts a transaction with the provided options.
-func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
-	return d.db.BeginTx(ctx, opts)
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := d.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, d.wrapErr(err)
+	}
+	return &Tx{db: d, tx: tx}, nil
 }
 
 // Close closes the database and prevents new queries from starting.
 func (d *DB) Close() error {
-	return d.db.Close()
+	return d.wrapErr(d.db.Close())
 }
 
 // Conn returns a single connection by either opening a new connection
 // or returning an existing connection from the connection pool.
-func (d *DB) Conn(ctx context.Context) (*sql.Conn, error) {
-	return d.db.Conn(ctx)
+func (d *DB) Conn(ctx context.Context) (*Conn, error) {
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, d.wrapErr(err)
+	}
+	return &Conn{db: d, conn: conn}, nil
 }
 
 // SQLDriver returns the underlying database/sql/driver.Driver.
@@ -128,25 +259,27 @@ func (d *DB) SQLDriver() sqldriver.Driver {
 // Exec executes a query without returning any rows.
 // The args are for any placeholder parameters in the query.
 func (d *DB) Exec(query string, args ...any) (sql.Result, error) {
-	return d.db.Exec(query, args...)
+	res, err := d.db.Exec(d.rebindQuery(query), args...)
+	return res, d.wrapErr(err)
 }
 
 // ExecContext executes a query without returning any rows.
 // The args are for any placeholder parameters in the query.
 func (d *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
-	return d.db.ExecContext(ctx, query, args...)
+	res, err := d.db.ExecContext(ctx, d.rebindQuery(query), args...)
+	return res, d.wrapErr(err)
 }
 
 // Ping verifies a connection to the database is still alive,
 // establishing a connection if necessary.
 func (d *DB) Ping() error {
-	return d.db.Ping()
+	return d.wrapErr(d.db.Ping())
 }
 
 // PingContext verifies a connection to the database is still alive,
 // establishing a connection if necessary.
 func (d *DB) PingContext(ctx context.Context) error {
-	return d.db.PingContext(ctx)
+	return d.wrapErr(d.db.PingContext(ctx))
 }
 
 // Prepare creates a prepared statement for later queries or executions.
@@ -154,7 +287,8 @@ func (d *DB) PingContext(ctx context.Context) error {
 // returned statement.
 // The caller must call the statement's Close method when the statement is no longer needed.
 func (d *DB) Prepare(query string) (*sql.Stmt, error) {
-	return d.db.Prepare(query)
+	stmt, err := d.db.Prepare(d.rebindQuery(query))
+	return stmt, d.wrapErr(err)
 }
 
 // PrepareContext creates a prepared statement for later queries or executions.
@@ -162,33 +296,36 @@ func (d *DB) Prepare(query string) (*sql.Stmt, error) {
 // returned statement.
 // The caller must call the statement's Close method when the statement is no longer needed.
 func (d *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
-	return d.db.PrepareContext(ctx, query)
+	stmt, err := d.db.PrepareContext(ctx, d.rebindQuery(query))
+	return stmt, d.wrapErr(err)
 }
 
 // Query executes a query that returns rows, typically a SELECT.
 // The args are for any placeholder parameters in the query.
 func (d *DB) Query(query string, args ...any) (*sql.Rows, error) {
-	return d.db.Query(query, args...)
+	rows, err := d.db.Query(d.rebindQuery(query), args...)
+	return rows, d.wrapErr(err)
 }
 
 // QueryContext executes a query that returns rows, typically a SELECT.
 // The args are for any placeholder parameters in the query.
 func (d *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
-	return d.db.QueryContext(ctx, query, args...)
+	rows, err := d.db.QueryContext(ctx, d.rebindQuery(query), args...)
+	return rows, d.wrapErr(err)
 }
 
 // QueryRow executes a query that is expected to return at most one row.
 // QueryRow always returns a non-nil value. Errors are deferred until
-// Row's Scan method is called.
+// Row's Scan method is called, use the WrapRow to translate them.
 func (d *DB) QueryRow(query string, args ...any) *sql.Row {
-	return d.db.QueryRow(query, args...)
+	return d.db.QueryRow(d.rebindQuery(query), args...)
 }
 
 // QueryRowContext executes a query that is expected to return at most one row.
 // QueryRowContext always returns a non-nil value. Errors are deferred until
-// Row's Scan method is called.
+// Row's Scan method is called, use the WrapRow to translate them.
 func (d *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
-	return d.db.QueryRowContext(ctx, query, args...)
+	return d.db.QueryRowContext(ctx, d.rebindQuery(query), args...)
 }
 
 // SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
@@ -213,12 +350,21 @@ func (d *DB) Stats() sql.DBStats {
 }
 
 // RunInTransaction runs the given function in a transaction.
-func (d *DB) RunInTransaction(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *sql.Tx) error) error {
-	tx, err := d.db.BeginTx(ctx, opts)
+// The transaction is stored in the context passed to the function.
+// If the context already carries a transaction started by this DB,
+// the function is run within a savepoint of that transaction instead,
+// and the opts are ignored. This way the calls could be nested freely
+// without breaking the atomicity of the outermost transaction.
+func (d *DB) RunInTransaction(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *Tx) error) error {
+	if ts, ok := d.txState(ctx); ok {
+		return d.runInSavepoint(ctx, ts, fn)
+	}
+
+	tx, err := d.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
-	if err = fn(ctx, tx); err != nil {
+	if err = fn(d.withTxState(ctx, &txState{tx: tx}), tx); err != nil {
 		_ = tx.Rollback()
 		return err
 	}
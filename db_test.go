@@ -0,0 +1,192 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// errStubConflict is the error returned by the stub statements and rows.
+var errStubConflict = errors.New("stub: could not serialize access")
+
+// stubConnector is a database/sql connector of the stubConn.
+type stubConnector struct{}
+
+func (stubConnector) Connect(context.Context) (sqldriver.Conn, error) { return stubConn{}, nil }
+func (stubConnector) Driver() sqldriver.Driver                        { return nil }
+
+// stubConn is a connection, whose statements fail with the errStubConflict.
+type stubConn struct{}
+
+func (stubConn) Prepare(query string) (sqldriver.Stmt, error) { return stubStmt{query: query}, nil }
+func (stubConn) Close() error                                 { return nil }
+func (stubConn) Begin() (sqldriver.Tx, error)                 { return stubTx{}, nil }
+
+// stubTx is a transaction, that always succeeds.
+type stubTx struct{}
+
+func (stubTx) Commit() error   { return nil }
+func (stubTx) Rollback() error { return nil }
+
+// stubStmt is a statement, that fails with the errStubConflict on execution,
+// unless it creates or releases a savepoint.
+type stubStmt struct {
+	query string
+}
+
+func (stubStmt) Close() error  { return nil }
+func (stubStmt) NumInput() int { return -1 }
+
+func (s stubStmt) Exec([]sqldriver.Value) (sqldriver.Result, error) {
+	if strings.HasPrefix(s.query, "SAVEPOINT") || strings.HasPrefix(s.query, "RELEASE SAVEPOINT") {
+		return sqldriver.ResultNoRows, nil
+	}
+	return nil, errStubConflict
+}
+
+func (stubStmt) Query([]sqldriver.Value) (sqldriver.Rows, error) { return stubRows{}, nil }
+
+// stubRows are the rows, that fail with the errStubConflict on iteration.
+type stubRows struct{}
+
+func (stubRows) Columns() []string            { return []string{"id"} }
+func (stubRows) Close() error                 { return nil }
+func (stubRows) Next([]sqldriver.Value) error { return errStubConflict }
+
+// stubDriver is a driver.DB that recognizes only the errStubConflict on its own.
+type stubDriver struct {
+	db      *sql.DB
+	dialect string
+}
+
+func (d *stubDriver) Dialect() string    { return d.dialect }
+func (d *stubDriver) DriverName() string { return "stub" }
+
+func (d *stubDriver) ErrorCode(err error) bserr.Code {
+	if errors.Is(err, errStubConflict) {
+		return bserr.ConcurrentUpdate
+	}
+	return bserr.Unknown
+}
+
+func (d *stubDriver) DB() *sql.DB                  { return d.db }
+func (d *stubDriver) ErrorColumn(error) string     { return "" }
+func (d *stubDriver) ErrorTable(error) string      { return "" }
+func (d *stubDriver) ErrorConstraint(error) string { return "" }
+func (d *stubDriver) HasErrorDetails() bool        { return false }
+
+func newStubDB(t *testing.T, dialect string, opts ...Option) *DB {
+	t.Helper()
+	sqlDB := sql.OpenDB(stubConnector{})
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := NewDB(&stubDriver{db: sqlDB, dialect: dialect}, opts...)
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	return db
+}
+
+func TestDBSentinelErrors(t *testing.T) {
+	db := newStubDB(t, driver.DialectPostgres)
+
+	tests := []struct {
+		name string
+		err  error
+		code bserr.Code
+	}{
+		{name: "no rows", err: sql.ErrNoRows, code: bserr.NotFound},
+		{name: "wrapped no rows", err: fmt.Errorf("load: %w", sql.ErrNoRows), code: bserr.NotFound},
+		{name: "canceled", err: context.Canceled, code: bserr.Canceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, code: bserr.Timeout},
+		{name: "bad conn", err: sqldriver.ErrBadConn, code: bserr.ConnectionLost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := db.ErrorCode(tt.err); got != tt.code {
+				t.Errorf("ErrorCode() = %v, want %v", got, tt.code)
+			}
+			if got := bserr.CodeOf(db.TranslateError(tt.err)); got != tt.code {
+				t.Errorf("TranslateError() code = %v, want %v", got, tt.code)
+			}
+			if got := db.ErrorClass(tt.err); got != tt.code.Class() {
+				t.Errorf("ErrorClass() = %v, want %v", got, tt.code.Class())
+			}
+		})
+	}
+}
+
+func TestDBRowsAndStmtErrorTranslation(t *testing.T) {
+	ctx := context.Background()
+	db := newStubDB(t, driver.DialectPostgres, WithErrorTranslation())
+
+	sqlRows, err := db.QueryContext(ctx, "SELECT id FROM users")
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	rows := db.WrapRows(sqlRows)
+	defer rows.Close()
+	for rows.Next() {
+		t.Fatal("Next() = true, want false")
+	}
+	if err = rows.Err(); !errors.Is(err, bserr.ConcurrentUpdate) {
+		t.Errorf("Rows.Err() = %v, want %v", err, bserr.ConcurrentUpdate)
+	}
+
+	sqlStmt, err := db.PrepareContext(ctx, "UPDATE users SET name = ?")
+	if err != nil {
+		t.Fatalf("PrepareContext() error = %v", err)
+	}
+	stmt := db.WrapStmt(sqlStmt)
+	defer stmt.Close()
+	if _, err = stmt.ExecContext(ctx, "name"); !errors.Is(err, bserr.ConcurrentUpdate) {
+		t.Errorf("Stmt.ExecContext() error = %v, want %v", err, bserr.ConcurrentUpdate)
+	}
+}
+
+func TestRunInTransactionNestedSavepoints(t *testing.T) {
+	ctx := context.Background()
+	errFn := errors.New("nested failed")
+
+	db := newStubDB(t, driver.DialectPostgres)
+	err := db.RunInTransaction(ctx, nil, func(ctx context.Context, tx *Tx) error {
+		return db.RunInTransaction(ctx, nil, func(ctx context.Context, tx *Tx) error {
+			return errFn
+		})
+	})
+	if !errors.Is(err, errFn) || !errors.Is(err, errStubConflict) {
+		t.Errorf("RunInTransaction() error = %v, want both %v and %v", err, errFn, errStubConflict)
+	}
+
+	db = newStubDB(t, driver.DialectTiDB)
+	err = db.RunInTransaction(ctx, nil, func(ctx context.Context, tx *Tx) error {
+		return db.RunInTransaction(ctx, nil, func(ctx context.Context, tx *Tx) error {
+			t.Error("nested function called without savepoints")
+			return nil
+		})
+	})
+	if !errors.Is(err, ErrSavepointsNotSupported) {
+		t.Errorf("RunInTransaction() error = %v, want %v", err, ErrSavepointsNotSupported)
+	}
+}
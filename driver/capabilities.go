@@ -0,0 +1,233 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+// PlaceholderStyle is the style of the query parameter placeholders.
+type PlaceholderStyle int
+
+const (
+	// PlaceholderQuestion is the question mark placeholder, i.e. "WHERE id = ?".
+	PlaceholderQuestion PlaceholderStyle = iota
+
+	// PlaceholderDollar is the numbered dollar placeholder, i.e. "WHERE id = $1".
+	PlaceholderDollar
+
+	// PlaceholderAtP is the numbered at-p placeholder, i.e. "WHERE id = @p1".
+	PlaceholderAtP
+
+	// PlaceholderColon is the numbered colon placeholder, i.e. "WHERE id = :1".
+	PlaceholderColon
+)
+
+// UpsertStyle is the syntax of the statement that inserts a row or updates the existing one.
+type UpsertStyle int
+
+const (
+	// UpsertNone means that the dialect doesn't support upserts.
+	UpsertNone UpsertStyle = iota
+
+	// UpsertOnConflict is the "INSERT ... ON CONFLICT ... DO UPDATE" syntax.
+	UpsertOnConflict
+
+	// UpsertOnDuplicateKey is the "INSERT ... ON DUPLICATE KEY UPDATE" syntax.
+	UpsertOnDuplicateKey
+
+	// UpsertMerge is the "MERGE INTO ... WHEN MATCHED ..." syntax.
+	UpsertMerge
+)
+
+// Capabilities describes the features supported by the database server,
+// so that the application could check what it can do,
+// instead of branching on the dialect name.
+type Capabilities struct {
+	// Returning reports whether the INSERT, UPDATE and DELETE statements
+	// support the RETURNING clause.
+	Returning bool
+
+	// Upsert is the supported upsert syntax.
+	Upsert UpsertStyle
+
+	// Savepoints reports whether the savepoints within a transaction are supported.
+	Savepoints bool
+
+	// SkipLocked reports whether the SELECT ... FOR UPDATE SKIP LOCKED is supported.
+	SkipLocked bool
+
+	// TransactionalDDL reports whether the DDL statements could be rolled back within a transaction.
+	TransactionalDDL bool
+
+	// ListenNotify reports whether the LISTEN and NOTIFY statements are supported.
+	ListenNotify bool
+
+	// MaxPlaceholders is the maximum number of the parameters of a single statement.
+	MaxPlaceholders int
+
+	// Placeholder is the style of the query parameter placeholders.
+	Placeholder PlaceholderStyle
+
+	// IdentifierQuote is the character used to quote the identifiers, i.e. '"' or '`'.
+	IdentifierQuote byte
+
+	// BackslashEscapes reports whether the backslash escapes the next character
+	// within the string literals, as in the MySQL by default.
+	BackslashEscapes bool
+
+	// HashComments reports whether the # starts a line comment.
+	HashComments bool
+
+	// BooleanType reports whether the dialect has a native boolean type.
+	BooleanType bool
+}
+
+// CapabilityDescriber is an optional interface that could be implemented by the DB
+// to describe the features supported by the connected database server.
+type CapabilityDescriber interface {
+	// Capabilities returns the features supported by the database server.
+	Capabilities() Capabilities
+}
+
+// DefaultCapabilities returns the capabilities of the given dialect,
+// for the server with given version and flavor.
+// The unknown dialects are described with the SQL standard defaults.
+func DefaultCapabilities(dialect string, info ServerInfo) Capabilities {
+	v := info.ServerVersion
+	switch dialect {
+	case DialectPostgres:
+		if info.Flavor == FlavorRedshift {
+			return Capabilities{
+				Upsert:           UpsertMerge,
+				TransactionalDDL: true,
+				MaxPlaceholders:  32767,
+				Placeholder:      PlaceholderDollar,
+				IdentifierQuote:  '"',
+				BooleanType:      true,
+			}
+		}
+		return Capabilities{
+			Returning:        true,
+			Upsert:           UpsertOnConflict,
+			Savepoints:       true,
+			SkipLocked:       true,
+			TransactionalDDL: true,
+			ListenNotify:     true,
+			MaxPlaceholders:  65535,
+			Placeholder:      PlaceholderDollar,
+			IdentifierQuote:  '"',
+			BooleanType:      true,
+		}
+	case DialectCockroach:
+		return Capabilities{
+			Returning:       true,
+			Upsert:          UpsertOnConflict,
+			Savepoints:      true,
+			SkipLocked:      v.AtLeast(23, 1, 0),
+			MaxPlaceholders: 65535,
+			Placeholder:     PlaceholderDollar,
+			IdentifierQuote: '"',
+			BooleanType:     true,
+		}
+	case DialectYugabyte:
+		return Capabilities{
+			Returning:        true,
+			Upsert:           UpsertOnConflict,
+			Savepoints:       true,
+			SkipLocked:       true,
+			TransactionalDDL: v.AtLeast(2, 18, 0),
+			MaxPlaceholders:  65535,
+			Placeholder:      PlaceholderDollar,
+			IdentifierQuote:  '"',
+			BooleanType:      true,
+		}
+	case DialectMySQL:
+		return Capabilities{
+			Upsert:           UpsertOnDuplicateKey,
+			Savepoints:       true,
+			SkipLocked:       v.AtLeast(8, 0, 1),
+			MaxPlaceholders:  65535,
+			Placeholder:      PlaceholderQuestion,
+			IdentifierQuote:  '`',
+			BackslashEscapes: true,
+			HashComments:     true,
+		}
+	case DialectMariaDB:
+		return Capabilities{
+			Returning:        v.AtLeast(10, 5, 0),
+			Upsert:           UpsertOnDuplicateKey,
+			Savepoints:       true,
+			SkipLocked:       v.AtLeast(10, 6, 0),
+			MaxPlaceholders:  65535,
+			Placeholder:      PlaceholderQuestion,
+			IdentifierQuote:  '`',
+			BackslashEscapes: true,
+			HashComments:     true,
+		}
+	case DialectTiDB:
+		return Capabilities{
+			Upsert:           UpsertOnDuplicateKey,
+			Savepoints:       v.AtLeast(6, 2, 0),
+			MaxPlaceholders:  65535,
+			Placeholder:      PlaceholderQuestion,
+			IdentifierQuote:  '`',
+			BackslashEscapes: true,
+			HashComments:     true,
+		}
+	case DialectSQLite:
+		maxPlaceholders := 999
+		if v.AtLeast(3, 32, 0) {
+			maxPlaceholders = 32766
+		}
+		upsert := UpsertNone
+		if v.AtLeast(3, 24, 0) {
+			upsert = UpsertOnConflict
+		}
+		return Capabilities{
+			Returning:        v.AtLeast(3, 35, 0),
+			Upsert:           upsert,
+			Savepoints:       true,
+			TransactionalDDL: true,
+			MaxPlaceholders:  maxPlaceholders,
+			Placeholder:      PlaceholderQuestion,
+			IdentifierQuote:  '"',
+		}
+	case DialectMSSQL:
+		// The server allows 2100 parameters, but go-mssqldb executes the queries
+		// with the sp_executesql, which takes two of them for the statement
+		// and its parameter definitions.
+		return Capabilities{
+			Upsert:           UpsertMerge,
+			Savepoints:       true,
+			TransactionalDDL: true,
+			MaxPlaceholders:  2098,
+			Placeholder:      PlaceholderAtP,
+			IdentifierQuote:  '"',
+		}
+	case DialectOracle:
+		return Capabilities{
+			Upsert:          UpsertMerge,
+			Savepoints:      true,
+			SkipLocked:      true,
+			MaxPlaceholders: 65535,
+			Placeholder:     PlaceholderColon,
+			IdentifierQuote: '"',
+			BooleanType:     v.AtLeast(23, 0, 0),
+		}
+	}
+	return Capabilities{
+		Savepoints:      true,
+		MaxPlaceholders: 999,
+		Placeholder:     PlaceholderQuestion,
+		IdentifierQuote: '"',
+	}
+}
@@ -54,3 +54,112 @@ type DB interface {
 	// such as column, table and constraint name.
 	HasErrorDetails() bool
 }
+
+// ErrorClassifier is an optional interface that could be implemented by the DB
+// to refine the classification of the errors, based on the driver specific details.
+type ErrorClassifier interface {
+	// ErrorClass returns the classification of the given error.
+	ErrorClass(err error) bserr.Class
+}
+
+// ErrorDetails contains the details of a database error.
+// The fields not supported by the driver are left empty.
+type ErrorDetails struct {
+	// SQLState is the SQLSTATE code of the error, i.e. "23505".
+	SQLState string
+
+	// Number is the native error number, i.e. the MySQL error number 1062.
+	Number int
+
+	// Severity is the severity of the error, i.e. "ERROR" or "FATAL".
+	Severity string
+
+	// Message is the primary error message.
+	Message string
+
+	// Detail is the secondary error message, carrying more detail about the problem.
+	Detail string
+
+	// Hint is the optional suggestion what to do about the problem.
+	Hint string
+
+	// Position is the cursor position of the error in the original query string.
+	// The first character has index 1, zero means the position is unknown.
+	Position int
+
+	// InternalQuery is the text of the internally generated query that failed,
+	// i.e. a query issued by a function.
+	InternalQuery string
+
+	// Schema is the name of the schema related to the error.
+	Schema string
+
+	// Table is the name of the table related to the error.
+	Table string
+
+	// Column is the name of the column related to the error.
+	Column string
+
+	// DataType is the name of the data type related to the error.
+	DataType string
+
+	// Constraint is the name of the constraint related to the error.
+	Constraint string
+
+	// Routine is the name of the source code routine reporting the error.
+	Routine string
+}
+
+// ErrorDetailer is an optional interface that could be implemented by the DB
+// to provide all the details of an error at once.
+type ErrorDetailer interface {
+	// ErrorDetails returns the details of the given error.
+	// It returns false if the error was not returned by the database.
+	ErrorDetails(err error) (ErrorDetails, bool)
+}
+
+// ServerInfo contains the information about the connected database server.
+type ServerInfo struct {
+	// Version is the version string reported by the server,
+	// i.e. the result of the SELECT VERSION() query.
+	Version string
+
+	// ServerVersion is the parsed version of the server.
+	// For the servers compatible with another dialect, such as TiDB,
+	// it is the version of the server itself, not of the emulated one.
+	ServerVersion ServerVersion
+
+	// Flavor is the variant of the server within its dialect,
+	// i.e. the FlavorAzureSQL of the mssql dialect.
+	// It is empty for the standard server of the dialect.
+	Flavor string
+
+	// Extensions are the names of the extensions installed in the database,
+	// i.e. the ExtensionTimescaleDB. Empty if the dialect doesn't support them.
+	Extensions []string
+}
+
+// HasExtension reports whether the extension with given name is installed in the database.
+func (si ServerInfo) HasExtension(name string) bool {
+	for _, ext := range si.Extensions {
+		if ext == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ServerInformer is an optional interface that could be implemented by the DB
+// to provide the information about the connected database server.
+type ServerInformer interface {
+	// ServerInfo returns the information about the database server.
+	ServerInfo() ServerInfo
+}
+
+// ArrayBinder is an optional interface that could be implemented by the DB,
+// whose database supports the array query parameters, such as the postgres.
+type ArrayBinder interface {
+	// ArrayArg returns the query arg that binds the given slice
+	// as a single array parameter.
+	ArrayArg(slice any) any
+}
@@ -38,4 +38,36 @@ const (
 
 	// DialectTiDB is the family name of the tidb driver.
 	DialectTiDB = "tidb"
+
+	// DialectMariaDB is the family name of the mariadb driver.
+	DialectMariaDB = "mariadb"
+)
+
+const (
+	// FlavorAzureSQL is the flavor of the mssql dialect for the Azure SQL Database
+	// and the Azure SQL Managed Instance.
+	FlavorAzureSQL = "azuresql"
+
+	// FlavorAurora is the flavor of the postgres dialect for the Amazon Aurora.
+	FlavorAurora = "aurora"
+
+	// FlavorRedshift is the flavor of the postgres dialect for the Amazon Redshift.
+	FlavorRedshift = "redshift"
+
+	// FlavorAlloyDB is the flavor of the postgres dialect for the Google AlloyDB.
+	FlavorAlloyDB = "alloydb"
+
+	// FlavorNeon is the flavor of the postgres dialect for the Neon serverless Postgres.
+	FlavorNeon = "neon"
+)
+
+const (
+	// ExtensionTimescaleDB is the name of the TimescaleDB postgres extension.
+	ExtensionTimescaleDB = "timescaledb"
+
+	// ExtensionCitus is the name of the Citus postgres extension.
+	ExtensionCitus = "citus"
+
+	// ExtensionPostGIS is the name of the PostGIS postgres extension.
+	ExtensionPostGIS = "postgis"
 )
@@ -0,0 +1,65 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/blockysource/blockysql/bserr"
+)
+
+// SentinelErrorCode returns the code of the driver independent errors,
+// such as the sql.ErrNoRows, context.DeadlineExceeded or network errors,
+// that may be wrapped in the given error.
+// The drivers should call it before inspecting their own error types,
+// so that all of them classify these errors identically.
+// It returns false if the error doesn't match any of them.
+func SentinelErrorCode(err error) (bserr.Code, bool) {
+	switch {
+	case err == nil:
+		return bserr.OK, true
+	case errors.Is(err, sql.ErrNoRows):
+		return bserr.NotFound, true
+	case errors.Is(err, sql.ErrTxDone):
+		return bserr.TxDone, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return bserr.Timeout, true
+	case errors.Is(err, context.Canceled):
+		return bserr.Canceled, true
+	case errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, sqldriver.ErrBadConn),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return bserr.ConnectionLost, true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return bserr.ConnectionLost, true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return bserr.Timeout, true
+		}
+		return bserr.ConnectionLost, true
+	}
+	return bserr.Unknown, false
+}
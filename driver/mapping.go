@@ -0,0 +1,84 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"strings"
+
+	"github.com/blockysource/blockysql/bserr"
+)
+
+// ErrorMapping maps the database errors matching its criteria to the bserr.Code.
+// At least one of the SQLState or Number must be set.
+type ErrorMapping struct {
+	// SQLState matches the SQLSTATE code of the error, i.e. "23505".
+	// A two character value matches the whole class of codes, i.e. "23".
+	SQLState string
+
+	// Number matches the native error number, i.e. the MySQL error number 1062.
+	Number int
+
+	// Message if not empty, matches only the errors whose message contains it.
+	// I.e. it allows to map the application errors raised with the same SQLSTATE.
+	Message string
+
+	// Code is the code of the matching errors.
+	Code bserr.Code
+}
+
+// Matches checks if the error with given details matches the mapping.
+func (m ErrorMapping) Matches(details ErrorDetails) bool {
+	if m.SQLState == "" && m.Number == 0 {
+		return false
+	}
+
+	if m.SQLState != "" {
+		if len(m.SQLState) == 2 {
+			if !strings.HasPrefix(details.SQLState, m.SQLState) {
+				return false
+			}
+		} else if m.SQLState != details.SQLState {
+			return false
+		}
+	}
+
+	if m.Number != 0 && m.Number != details.Number {
+		return false
+	}
+
+	return m.Message == "" || strings.Contains(details.Message, m.Message)
+}
+
+// ErrorMappings is an ordered table of the error mappings.
+type ErrorMappings []ErrorMapping
+
+// Code returns the code of the first mapping that matches the error with given details.
+// It returns false if none of the mappings match.
+func (m ErrorMappings) Code(details ErrorDetails) (bserr.Code, bool) {
+	for _, mapping := range m {
+		if mapping.Matches(details) {
+			return mapping.Code, true
+		}
+	}
+	return bserr.Unknown, false
+}
+
+// With returns a new table where the given mappings precede the mappings of m,
+// so that they add or override the entries of m.
+func (m ErrorMappings) With(mappings ...ErrorMapping) ErrorMappings {
+	out := make(ErrorMappings, 0, len(mappings)+len(m))
+	out = append(out, mappings...)
+	return append(out, m...)
+}
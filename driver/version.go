@@ -0,0 +1,87 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ServerVersion is the parsed version of the database server.
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// versionRegexp matches the first version number in the version string.
+var versionRegexp = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// ParseServerVersion parses the first version number of the form
+// major[.minor[.patch]] found in the given version string,
+// i.e. "8.0.34-log" or "PostgreSQL 15.4 on x86_64-pc-linux-gnu".
+// It returns false if the string doesn't contain a version number.
+func ParseServerVersion(s string) (ServerVersion, bool) {
+	m := versionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return ServerVersion{}, false
+	}
+
+	var v ServerVersion
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, true
+}
+
+// String returns the version in the major.minor.patch form.
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// IsZero reports whether the version is unknown.
+func (v ServerVersion) IsZero() bool {
+	return v == ServerVersion{}
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is
+// lower, equal or greater than the other version.
+func (v ServerVersion) Compare(other ServerVersion) int {
+	switch {
+	case v.Major != other.Major:
+		return compareInt(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return compareInt(v.Minor, other.Minor)
+	default:
+		return compareInt(v.Patch, other.Patch)
+	}
+}
+
+// AtLeast reports whether the version is greater than or equal to the given one.
+func (v ServerVersion) AtLeast(major, minor, patch int) bool {
+	return v.Compare(ServerVersion{Major: major, Minor: minor, Patch: patch}) >= 0
+}
+
+// compareInt returns -1, 0 or +1 depending on whether a is lower, equal or greater than b.
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+// ErrTooManyPlaceholders is returned when the expanded query has more placeholders
+// than the database allows in a single statement.
+var ErrTooManyPlaceholders = errors.New("blockysql: too many placeholders")
+
+// Array marks the slice arg to be bound as a single array parameter by the DB.Expand,
+// instead of being expanded into a list of placeholders, i.e.:
+//
+//	db.Expand("SELECT * FROM users WHERE id = ANY(?)", blockysql.Array(ids))
+//
+// It is supported by the drivers implementing the driver.ArrayBinder,
+// such as the postgres ones.
+func Array(slice any) any {
+	return arrayArg{slice: slice}
+}
+
+// arrayArg is the slice arg that is bound as an array parameter.
+type arrayArg struct {
+	slice any
+}
+
+// Value implements driver.Valuer.
+// The arrayArg is meant to be bound by the Expand,
+// thus it fails if it is passed to the query directly.
+func (arrayArg) Value() (sqldriver.Value, error) {
+	return nil, errors.New("blockysql: Array arg must be bound with the Expand")
+}
+
+// Expand expands the slice args of the query with the question mark placeholders
+// into the lists of placeholders, and converts them into the placeholder style
+// of the given capabilities, i.e. for the driver.PlaceholderDollar:
+//
+//	SELECT * FROM users WHERE tenant = ? AND id IN (?)
+//
+// called with the args "acme" and []int64{1, 2, 3} becomes:
+//
+//	SELECT * FROM users WHERE tenant = $1 AND id IN ($2, $3, $4)
+//
+// with the args "acme", 1, 2 and 3.
+// The []byte and the driver.Valuer args are never expanded, and the empty slices
+// are rejected. If the expanded query has more placeholders than the
+// capabilities MaxPlaceholders, an error matching the ErrTooManyPlaceholders is returned.
+// The Array args are not supported, use the DB.Expand to bind them.
+func Expand(caps driver.Capabilities, query string, args ...any) (string, []any, error) {
+	return expand(caps, nil, false, query, args)
+}
+
+// Expand expands the slice args of the query with the question mark placeholders
+// into the lists of placeholders of the connected database.
+// The Array args are bound as the array parameters, if the driver
+// implements the driver.ArrayBinder. See the Expand function for details.
+func (d *DB) Expand(query string, args ...any) (string, []any, error) {
+	var bindArray func(any) any
+	if ab, ok := d.driver.(driver.ArrayBinder); ok {
+		bindArray = ab.ArrayArg
+	}
+	return expand(d.Capabilities(), bindArray, d.rebind, query, args)
+}
+
+// expand expands the slice args of the query.
+// If bindArray is nil, the Array args are rejected.
+// If escape is true, the literal question marks are left doubled,
+// so that the query could be rebound again. They are also left doubled
+// for the driver.PlaceholderQuestion style, where a single question mark
+// would be a placeholder.
+func expand(caps driver.Capabilities, bindArray func(any) any, escape bool, query string, args []any) (string, []any, error) {
+	var (
+		sb       strings.Builder
+		expanded = make([]any, 0, len(args))
+		l        = newLexer(caps)
+		n        int
+	)
+	sb.Grow(len(query) + 8)
+	for i := 0; i < len(query); {
+		if end, ok := l.skipNonCode(query, i); ok {
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		if c != '?' {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+
+		if i+1 < len(query) && query[i+1] == '?' {
+			if escape || caps.Placeholder == driver.PlaceholderQuestion {
+				sb.WriteByte('?')
+			}
+			sb.WriteByte('?')
+			i += 2
+			continue
+		}
+		i++
+
+		if n == len(args) {
+			return "", nil, fmt.Errorf("blockysql: query has more placeholders than the %d args", len(args))
+		}
+		arg := args[n]
+		n++
+
+		if aa, ok := arg.(arrayArg); ok {
+			if bindArray == nil {
+				return "", nil, errors.New("blockysql: Array args are not supported by the driver")
+			}
+			expanded = append(expanded, bindArray(aa.slice))
+			writePlaceholder(&sb, caps.Placeholder, len(expanded))
+			continue
+		}
+
+		v, ok := expandable(arg)
+		if !ok {
+			expanded = append(expanded, arg)
+			writePlaceholder(&sb, caps.Placeholder, len(expanded))
+			continue
+		}
+
+		if v.Len() == 0 {
+			return "", nil, fmt.Errorf("blockysql: empty slice passed as the arg %d", n)
+		}
+		for j := 0; j < v.Len(); j++ {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			expanded = append(expanded, v.Index(j).Interface())
+			writePlaceholder(&sb, caps.Placeholder, len(expanded))
+		}
+	}
+
+	if n != len(args) {
+		return "", nil, fmt.Errorf("blockysql: query has %d placeholders, got %d args", n, len(args))
+	}
+	if caps.MaxPlaceholders > 0 && len(expanded) > caps.MaxPlaceholders {
+		return "", nil, fmt.Errorf("%w: the expanded query has %d placeholders, the maximum is %d",
+			ErrTooManyPlaceholders, len(expanded), caps.MaxPlaceholders)
+	}
+	return sb.String(), expanded, nil
+}
+
+// expandable returns the reflected value of the arg if it should be expanded,
+// that is if it is a slice or an array other than the []byte, and it doesn't
+// implement the driver.Valuer.
+func expandable(arg any) (reflect.Value, bool) {
+	if arg == nil {
+		return reflect.Value{}, false
+	}
+	if _, ok := arg.(sqldriver.Valuer); ok {
+		return reflect.Value{}, false
+	}
+
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return reflect.Value{}, false
+		}
+		return v, true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialect   string
+		query     string
+		args      []any
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "question",
+			dialect:   driver.DialectMySQL,
+			query:     "SELECT * FROM users WHERE tenant = ? AND id IN (?)",
+			args:      []any{"acme", []int64{1, 2, 3}},
+			wantQuery: "SELECT * FROM users WHERE tenant = ? AND id IN (?, ?, ?)",
+			wantArgs:  []any{"acme", int64(1), int64(2), int64(3)},
+		},
+		{
+			name:      "dollar",
+			dialect:   driver.DialectPostgres,
+			query:     "SELECT * FROM users WHERE id IN (?) AND tenant = ?",
+			args:      []any{[]int{1, 2}, "acme"},
+			wantQuery: "SELECT * FROM users WHERE id IN ($1, $2) AND tenant = $3",
+			wantArgs:  []any{1, 2, "acme"},
+		},
+		{
+			name:      "at p with array",
+			dialect:   driver.DialectMSSQL,
+			query:     "SELECT * FROM users WHERE name IN (?)",
+			args:      []any{[2]string{"a", "b"}},
+			wantQuery: "SELECT * FROM users WHERE name IN (@p1, @p2)",
+			wantArgs:  []any{"a", "b"},
+		},
+		{
+			name:      "bytes not expanded",
+			dialect:   driver.DialectOracle,
+			query:     "SELECT * FROM files WHERE data = ?",
+			args:      []any{[]byte("data")},
+			wantQuery: "SELECT * FROM files WHERE data = :1",
+			wantArgs:  []any{[]byte("data")},
+		},
+		{
+			name:      "mysql backslash escapes",
+			dialect:   driver.DialectMySQL,
+			query:     "SELECT * FROM t WHERE s = 'it\\'s ?' AND id IN (?)",
+			args:      []any{[]int{1, 2}},
+			wantQuery: "SELECT * FROM t WHERE s = 'it\\'s ?' AND id IN (?, ?)",
+			wantArgs:  []any{1, 2},
+		},
+		{
+			name:      "mysql hash comment",
+			dialect:   driver.DialectMySQL,
+			query:     "SELECT * FROM t # id IN (?)\nWHERE id IN (?)",
+			args:      []any{[]int{1, 2}},
+			wantQuery: "SELECT * FROM t # id IN (?)\nWHERE id IN (?, ?)",
+			wantArgs:  []any{1, 2},
+		},
+		{
+			name:      "mysql doubled question mark",
+			dialect:   driver.DialectMySQL,
+			query:     "SELECT * FROM t WHERE a = ?? AND id IN (?)",
+			args:      []any{[]int{1, 2}},
+			wantQuery: "SELECT * FROM t WHERE a = ?? AND id IN (?, ?)",
+			wantArgs:  []any{1, 2},
+		},
+		{
+			name:      "postgres doubled question mark",
+			dialect:   driver.DialectPostgres,
+			query:     "SELECT * FROM t WHERE data ?? 'key' AND id IN (?)",
+			args:      []any{[]int{1, 2}},
+			wantQuery: "SELECT * FROM t WHERE data ? 'key' AND id IN ($1, $2)",
+			wantArgs:  []any{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := Expand(capsOf(tt.dialect), tt.query, tt.args...)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("Expand() query = %q, want %q", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Expand() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	caps := capsOf(driver.DialectMSSQL)
+	query := "SELECT * FROM users WHERE tenant = ? AND id IN (?)"
+
+	_, _, err := Expand(caps, query, "acme", make([]int, caps.MaxPlaceholders))
+	if !errors.Is(err, ErrTooManyPlaceholders) {
+		t.Errorf("Expand() too many placeholders error = %v, want %v", err, ErrTooManyPlaceholders)
+	}
+
+	tests := []struct {
+		name string
+		args []any
+	}{
+		{name: "empty slice", args: []any{"acme", []int{}}},
+		{name: "too few args", args: []any{"acme"}},
+		{name: "too many args", args: []any{"acme", 1, 2}},
+		{name: "unsupported array", args: []any{"acme", Array([]int{1})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Expand(caps, query, tt.args...); err == nil {
+				t.Error("Expand() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgshared
+
+import (
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// errorMappings maps the SQLSTATE codes of the postgres protocol to the bserr codes.
+// The exact codes precede the classes of codes.
+var errorMappings = driver.ErrorMappings{
+	{SQLState: "23505", Code: bserr.UniqueViolation},
+	{SQLState: "23503", Code: bserr.ForeignKeyViolation},
+	{SQLState: "23502", Code: bserr.NotNullViolation},
+	{SQLState: "23514", Code: bserr.CheckViolation},
+	{SQLState: "23P01", Code: bserr.ExclusionViolation},
+	{SQLState: "22001", Code: bserr.StringTooLong},
+	{SQLState: "22003", Code: bserr.NumericOverflow},
+	{SQLState: "22P02", Code: bserr.InvalidTextRepresentation},
+	{SQLState: "25006", Code: bserr.ReadOnlyTransaction},
+	{SQLState: "42501", Code: bserr.PermissionDenied},
+	{SQLState: "40001", Code: bserr.ConcurrentUpdate}, // Serialization failure.
+	{SQLState: "CR000", Code: bserr.ConcurrentUpdate}, // CockroachDB serialization failure.
+	{SQLState: "40P01", Code: bserr.Deadlock},
+	{SQLState: "55P03", Code: bserr.LockNotAvailable},
+	{SQLState: "57014", Code: bserr.Canceled},
+	{SQLState: "42P01", Code: bserr.TableNotFound},
+	{SQLState: "53200", Code: bserr.OutOfMemory},
+	{SQLState: "53100", Code: bserr.OutOfDisk},
+	{SQLState: "53300", Code: bserr.TooManyConnections},
+	{SQLState: "57000", Code: bserr.Timeout},
+	{SQLState: "57P01", Code: bserr.ConnectionLost},
+	{SQLState: "57P02", Code: bserr.ConnectionLost},
+	{SQLState: "57P03", Code: bserr.ConnectionLost},
+	{SQLState: "XX000", Code: bserr.InternalError},
+	{SQLState: "28P01", Code: bserr.AuthenticationFailed}, // Invalid password.
+	{SQLState: "08", Code: bserr.ConnectionLost},
+	{SQLState: "28", Code: bserr.AuthenticationFailed}, // Invalid authorization specification.
+	{SQLState: "42", Code: bserr.InvalidInputSyntax},
+	{SQLState: "22", Code: bserr.DataException},
+	{SQLState: "23", Code: bserr.ConstraintViolation},
+	{SQLState: "54", Code: bserr.InternalError}, // Program limit exceeded.
+	{SQLState: "XX", Code: bserr.InternalError},
+}
+
+// ErrorMappings returns a copy of the table that maps the SQLSTATE codes
+// of the postgres protocol to the bserr codes.
+func ErrorMappings() driver.ErrorMappings {
+	return append(driver.ErrorMappings(nil), errorMappings...)
+}
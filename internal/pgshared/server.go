@@ -0,0 +1,95 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pgshared contains the code shared by the postgres drivers,
+// such as the detection of the database server.
+package pgshared
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+// flavorQuery checks the functions and settings specific to the postgres flavors.
+const flavorQuery = `SELECT
+	EXISTS (SELECT 1 FROM pg_proc WHERE proname = 'aurora_version'),
+	EXISTS (SELECT 1 FROM pg_settings WHERE name LIKE 'alloydb%'),
+	EXISTS (SELECT 1 FROM pg_settings WHERE name LIKE 'neon.%')`
+
+// ProbeServer returns the dialect and the information about the database server.
+// The dialect and the server version are decided by the result of the SELECT VERSION() query, i.e.:
+//   - PostgreSQL 15.4 on x86_64-pc-linux-gnu, compiled by gcc ... (postgres)
+//   - CockroachDB CCL v23.1.8 (x86_64-pc-linux-gnu, built ...) (cockroach)
+//   - PostgreSQL 11.2-YB-2.18.2.1-b0 on x86_64-pc-linux-gnu, compiled by ... (yugabyte)
+//   - PostgreSQL 8.0.2 on i686-pc-linux-gnu, compiled by GCC ..., Redshift 1.0.54052 (postgres, redshift)
+//
+// The flavors that report the postgres version are detected by their functions and settings.
+func ProbeServer(ctx context.Context, db *sql.DB) (string, driver.ServerInfo, error) {
+	var si driver.ServerInfo
+	row := db.QueryRowContext(ctx, "SELECT VERSION()")
+	if err := row.Scan(&si.Version); err != nil {
+		return "", si, err
+	}
+
+	if isCockroach := strings.Contains(si.Version, "CockroachDB"); isCockroach {
+		si.ServerVersion, _ = driver.ParseServerVersion(si.Version)
+		return driver.DialectCockroach, si, nil
+	}
+
+	if isRedshift := strings.Contains(si.Version, "Redshift"); isRedshift {
+		_, redshiftVersion, _ := strings.Cut(si.Version, "Redshift")
+		si.ServerVersion, _ = driver.ParseServerVersion(redshiftVersion)
+		si.Flavor = driver.FlavorRedshift
+		return driver.DialectPostgres, si, nil
+	}
+
+	dialect := driver.DialectPostgres
+	if _, ybVersion, isYugaByte := strings.Cut(si.Version, "-YB-"); isYugaByte {
+		si.ServerVersion, _ = driver.ParseServerVersion(ybVersion)
+		dialect = driver.DialectYugabyte
+	} else {
+		si.ServerVersion, _ = driver.ParseServerVersion(si.Version)
+
+		var isAurora, isAlloyDB, isNeon bool
+		if err := db.QueryRowContext(ctx, flavorQuery).Scan(&isAurora, &isAlloyDB, &isNeon); err != nil {
+			return "", si, err
+		}
+		switch {
+		case isAurora:
+			si.Flavor = driver.FlavorAurora
+		case isAlloyDB:
+			si.Flavor = driver.FlavorAlloyDB
+		case isNeon:
+			si.Flavor = driver.FlavorNeon
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT extname FROM pg_extension ORDER BY extname")
+	if err != nil {
+		return "", si, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return "", si, err
+		}
+		si.Extensions = append(si.Extensions, name)
+	}
+	return dialect, si, rows.Err()
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"strings"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+// lexer describes the lexical rules of a dialect, that are needed to skip
+// the string literals, quoted identifiers and comments while scanning
+// the query for the placeholders.
+type lexer struct {
+	// brackets reports whether the [identifier] is a quoted identifier,
+	// as in the SQL Server, rather than an array subscript.
+	brackets bool
+
+	// backslashes reports whether the backslash escapes the next character
+	// within the '...' and "..." literals, as in the MySQL.
+	backslashes bool
+
+	// hashComments reports whether the # starts a line comment, as in the MySQL.
+	hashComments bool
+}
+
+// newLexer returns the lexer of the dialect with given capabilities.
+func newLexer(caps driver.Capabilities) lexer {
+	return lexer{
+		brackets:     caps.Placeholder == driver.PlaceholderAtP,
+		backslashes:  caps.BackslashEscapes,
+		hashComments: caps.HashComments,
+	}
+}
+
+// skipNonCode checks if the query at position i starts a string literal,
+// a quoted identifier, a comment or a postgres :: cast, that must be copied
+// as is while scanning the query for the placeholders.
+// It returns the position right after its end, or false if the query
+// at position i is a regular SQL code.
+// An unterminated literal or comment ends with the query.
+func (l lexer) skipNonCode(q string, i int) (int, bool) {
+	switch c := q[i]; {
+	case c == '\'':
+		// The postgres escape string constant, i.e. E'it\'s'.
+		escapes := l.backslashes || (i > 0 && (q[i-1] == 'E' || q[i-1] == 'e') && (i == 1 || !isIdentChar(q[i-2])))
+		return skipQuoted(q, i, '\'', escapes), true
+	case c == '"':
+		return skipQuoted(q, i, '"', l.backslashes), true
+	case c == '`':
+		return skipQuoted(q, i, '`', false), true
+	case c == '[' && l.brackets:
+		return skipQuoted(q, i, ']', false), true
+	case c == '-' && strings.HasPrefix(q[i:], "--"):
+		return skipLineComment(q, i), true
+	case c == '#' && l.hashComments:
+		return skipLineComment(q, i), true
+	case c == '/' && strings.HasPrefix(q[i:], "/*"):
+		return skipBlockComment(q, i), true
+	case c == ':' && strings.HasPrefix(q[i:], "::"):
+		return i + 2, true
+	case c == '$':
+		return skipDollarQuoted(q, i)
+	}
+	return i, false
+}
+
+// skipQuoted returns the position after the literal that starts at position i
+// and ends with the given closing character. The doubled closing character
+// is an escaped one, and so is the character after a backslash if the escapes are enabled.
+func skipQuoted(q string, i int, closing byte, escapes bool) int {
+	for j := i + 1; j < len(q); j++ {
+		switch q[j] {
+		case '\\':
+			if escapes {
+				j++
+			}
+		case closing:
+			if j+1 < len(q) && q[j+1] == closing {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(q)
+}
+
+// skipLineComment returns the position after the line comment that starts at position i.
+func skipLineComment(q string, i int) int {
+	if end := strings.IndexByte(q[i:], '\n'); end >= 0 {
+		return i + end + 1
+	}
+	return len(q)
+}
+
+// skipBlockComment returns the position after the block comment that starts at position i.
+// The nested block comments are supported, as in postgres.
+func skipBlockComment(q string, i int) int {
+	depth := 0
+	for j := i; j < len(q)-1; j++ {
+		switch {
+		case q[j] == '/' && q[j+1] == '*':
+			depth++
+			j++
+		case q[j] == '*' && q[j+1] == '/':
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(q)
+}
+
+// skipDollarQuoted checks if the query at position i starts a postgres
+// dollar-quoted string constant, i.e. $$it's$$ or $fn$SELECT 1$fn$,
+// and returns the position after its end.
+func skipDollarQuoted(q string, i int) (int, bool) {
+	if i > 0 && isIdentChar(q[i-1]) {
+		return i, false
+	}
+
+	// The tag follows the identifier rules, but can't contain a dollar sign.
+	j := i + 1
+	for j < len(q) && q[j] != '$' {
+		if !isIdentChar(q[j]) || (j == i+1 && isDigit(q[j])) {
+			return i, false
+		}
+		j++
+	}
+	if j >= len(q) {
+		return i, false
+	}
+
+	tag := q[i : j+1]
+	end := strings.Index(q[j+1:], tag)
+	if end < 0 {
+		return len(q), true
+	}
+	return j + 1 + end + len(tag), true
+}
+
+// isIdentChar reports whether c could be a part of an unquoted identifier.
+func isIdentChar(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// isDigit reports whether c is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
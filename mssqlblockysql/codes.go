@@ -0,0 +1,67 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mssqlblockysql
+
+import (
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// defaultErrorMappings maps the SQL Server error numbers to the bserr codes.
+// See https://learn.microsoft.com/en-us/sql/relational-databases/errors-events/database-engine-events-and-errors.
+var defaultErrorMappings = driver.ErrorMappings{
+	{Number: 2627, Code: bserr.UniqueViolation},                            // Violation of PRIMARY KEY or UNIQUE KEY constraint.
+	{Number: 2601, Code: bserr.UniqueViolation},                            // Cannot insert duplicate key row with unique index.
+	{Number: 547, Message: "CHECK constraint", Code: bserr.CheckViolation}, // The statement conflicted with the CHECK constraint.
+	{Number: 547, Code: bserr.ForeignKeyViolation},                         // The statement conflicted with the FOREIGN KEY or REFERENCE constraint.
+	{Number: 515, Code: bserr.NotNullViolation},                            // Cannot insert the value NULL into column.
+	{Number: 2628, Code: bserr.StringTooLong},                              // String or binary data would be truncated in table.
+	{Number: 8152, Code: bserr.StringTooLong},                              // String or binary data would be truncated.
+	{Number: 8115, Code: bserr.NumericOverflow},                            // Arithmetic overflow error.
+	{Number: 220, Code: bserr.NumericOverflow},                             // Arithmetic overflow error for data type.
+	{Number: 245, Code: bserr.InvalidTextRepresentation},                   // Conversion failed.
+	{Number: 8114, Code: bserr.InvalidTextRepresentation},                  // Error converting data type.
+	{Number: 8134, Code: bserr.DataException},                              // Divide by zero error encountered.
+	{Number: 1205, Code: bserr.Deadlock},                                   // Transaction was deadlocked.
+	{Number: 1222, Code: bserr.LockNotAvailable},                           // Lock request time out period exceeded.
+	{Number: 3960, Code: bserr.ConcurrentUpdate},                           // Snapshot isolation transaction aborted due to update conflict.
+	{Number: 208, Code: bserr.TableNotFound},                               // Invalid object name.
+	{Number: 102, Code: bserr.InvalidInputSyntax},                          // Incorrect syntax.
+	{Number: 156, Code: bserr.InvalidInputSyntax},                          // Incorrect syntax near the keyword.
+	{Number: 207, Code: bserr.InvalidInputSyntax},                          // Invalid column name.
+	{Number: 18456, Code: bserr.AuthenticationFailed},                      // Login failed for user.
+	{Number: 4060, Code: bserr.AuthenticationFailed},                       // Cannot open database requested by the login.
+	{Number: 229, Code: bserr.PermissionDenied},                            // The permission was denied on the object.
+	{Number: 230, Code: bserr.PermissionDenied},                            // The permission was denied on the column.
+	{Number: 262, Code: bserr.PermissionDenied},                            // The permission was denied in the database.
+	{Number: 3902, Code: bserr.TxDone},                                     // The COMMIT TRANSACTION request has no corresponding BEGIN TRANSACTION.
+	{Number: 3903, Code: bserr.TxDone},                                     // The ROLLBACK TRANSACTION request has no corresponding BEGIN TRANSACTION.
+	{Number: 3906, Code: bserr.ReadOnlyTransaction},                        // Failed to update database because the database is read-only.
+	{Number: 701, Code: bserr.OutOfMemory},                                 // There is insufficient system memory.
+	{Number: 1105, Code: bserr.OutOfDisk},                                  // Could not allocate space, the filegroup is full.
+	{Number: 9002, Code: bserr.OutOfDisk},                                  // The transaction log for database is full.
+	{Number: 40501, Code: bserr.TooManyConnections},                        // Azure SQL: The service is currently busy.
+	{Number: 10928, Code: bserr.TooManyConnections},                        // Azure SQL: The resource limit has been reached.
+	{Number: 10929, Code: bserr.TooManyConnections},                        // Azure SQL: The minimum guarantee has not been met.
+	{Number: 40613, Code: bserr.ConnectionLost},                            // Azure SQL: Database is not currently available.
+	{Number: 40197, Code: bserr.ConnectionLost},                            // Azure SQL: The service has encountered an error processing the request.
+	{Number: 3980, Code: bserr.Canceled},                                   // The request failed to run because the batch is aborted.
+}
+
+// DefaultErrorMappings returns a copy of the default table
+// that maps the SQL Server error numbers to the bserr codes.
+func DefaultErrorMappings() driver.ErrorMappings {
+	return append(driver.ErrorMappings(nil), defaultErrorMappings...)
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mssqlblockysql
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// uniqueConstraintRegexp matches the unique constraint error (2627), i.e.:
+	//  Violation of UNIQUE KEY constraint 'UQ_users_email'. Cannot insert duplicate key in object 'dbo.users'.
+	uniqueConstraintRegexp = regexp.MustCompile(`constraint '([^']+)'\. Cannot insert duplicate key in object '([^']+)'`)
+
+	// uniqueIndexRegexp matches the unique index error (2601), i.e.:
+	//  Cannot insert duplicate key row in object 'dbo.users' with unique index 'IX_users_email'.
+	uniqueIndexRegexp = regexp.MustCompile(`duplicate key row in object '([^']+)' with unique index '([^']+)'`)
+
+	// conflictRegexp matches the foreign key and check constraint errors (547), i.e.:
+	//  The INSERT statement conflicted with the FOREIGN KEY constraint "FK_orders_users".
+	//  The conflict occurred in database "shop", table "dbo.users", column 'id'.
+	conflictRegexp = regexp.MustCompile(`constraint "([^"]+)"\. The conflict occurred in database "[^"]+", table "([^"]+)"(?:, column '([^']+)')?`)
+
+	// notNullRegexp matches the not null error (515), i.e.:
+	//  Cannot insert the value NULL into column 'name', table 'shop.dbo.users'; column does not allow nulls.
+	notNullRegexp = regexp.MustCompile(`into column '([^']+)', table '([^']+)'`)
+
+	// invalidObjectRegexp matches the invalid object name error (208), i.e.:
+	//  Invalid object name 'dbo.users'.
+	invalidObjectRegexp = regexp.MustCompile(`Invalid object name '([^']+)'`)
+)
+
+// messageDetails contains the names extracted from the error message.
+type messageDetails struct {
+	schema     string
+	table      string
+	column     string
+	constraint string
+}
+
+// parseErrorMessage extracts the schema, table, column and constraint names
+// from the message of the error with given number.
+// The table of the foreign key errors is the table reported by SQL Server,
+// that is the referenced table on insert and the referencing table on delete.
+func parseErrorMessage(number int32, msg string) messageDetails {
+	var md messageDetails
+	switch number {
+	case 2627:
+		if m := uniqueConstraintRegexp.FindStringSubmatch(msg); m != nil {
+			md.constraint = m[1]
+			md.schema, md.table = splitObjectName(m[2])
+		}
+	case 2601:
+		if m := uniqueIndexRegexp.FindStringSubmatch(msg); m != nil {
+			md.schema, md.table = splitObjectName(m[1])
+			md.constraint = m[2]
+		}
+	case 547:
+		if m := conflictRegexp.FindStringSubmatch(msg); m != nil {
+			md.constraint = m[1]
+			md.schema, md.table = splitObjectName(m[2])
+			md.column = m[3]
+		}
+	case 515:
+		if m := notNullRegexp.FindStringSubmatch(msg); m != nil {
+			md.column = m[1]
+			md.schema, md.table = splitObjectName(m[2])
+		}
+	case 208:
+		if m := invalidObjectRegexp.FindStringSubmatch(msg); m != nil {
+			md.schema, md.table = splitObjectName(m[1])
+		}
+	}
+	return md
+}
+
+// splitObjectName splits the object name of the form [database.][schema.]name
+// into the schema and the name.
+func splitObjectName(name string) (schema, object string) {
+	parts := strings.Split(name, ".")
+	object = parts[len(parts)-1]
+	if len(parts) > 1 {
+		schema = parts[len(parts)-2]
+	}
+	return schema, object
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlblockysql
+
+import (
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// defaultErrorMappings maps the MySQL, MariaDB and TiDB error numbers
+// and SQLSTATE codes to the bserr codes. The error numbers precede the SQLSTATE codes.
+var defaultErrorMappings = driver.ErrorMappings{
+	{Number: 1040, Code: bserr.TooManyConnections},
+	{Number: 1045, Code: bserr.AuthenticationFailed}, // Access denied for user.
+	{Number: 1698, Code: bserr.AuthenticationFailed}, // Access denied for user without password.
+	{Number: 1251, Code: bserr.AuthenticationFailed}, // Client does not support authentication protocol.
+	{Number: 1044, Code: bserr.PermissionDenied},     // Access denied for user to database.
+	{Number: 1142, Code: bserr.PermissionDenied},     // Command denied to user for table.
+	{Number: 1022, Code: bserr.UniqueViolation},
+	{Number: 1062, Code: bserr.UniqueViolation},
+	{Number: 1169, Code: bserr.UniqueViolation},
+	{Number: 1064, Code: bserr.InvalidInputSyntax},
+	{Number: 1065, Code: bserr.InvalidInputSyntax},
+	{Number: 1067, Code: bserr.InvalidInputSyntax},
+	{Number: 1072, Code: bserr.InvalidInputSyntax},
+	{Number: 1087, Code: bserr.InvalidInputSyntax},
+	{Number: 1090, Code: bserr.InvalidInputSyntax},
+	{Number: 1146, Code: bserr.TableNotFound},
+	{Number: 1212, Code: bserr.ForeignKeyViolation},
+	{Number: 1216, Code: bserr.ForeignKeyViolation},
+	{Number: 1217, Code: bserr.ForeignKeyViolation},
+	{Number: 1451, Code: bserr.ForeignKeyViolation},
+	{Number: 1452, Code: bserr.ForeignKeyViolation},
+	{Number: 1453, Code: bserr.ForeignKeyViolation},
+	{Number: 1557, Code: bserr.ForeignKeyViolation},
+	{Number: 1825, Code: bserr.ForeignKeyViolation},
+	{Number: 1826, Code: bserr.ForeignKeyViolation},
+	{Number: 3819, Code: bserr.CheckViolation},
+	{Number: 4025, Code: bserr.CheckViolation}, // MariaDB check constraint violated.
+	{Number: 3820, Code: bserr.ConstraintViolation},
+	{Number: 3822, Code: bserr.ConstraintViolation},
+	{Number: 3823, Code: bserr.ConstraintViolation},
+	{Number: 1048, Code: bserr.NotNullViolation},
+	{Number: 1364, Code: bserr.NotNullViolation},
+	{Number: 1213, Code: bserr.Deadlock},
+	{Number: 1205, Code: bserr.LockNotAvailable},
+	{Number: 3572, Code: bserr.LockNotAvailable},
+	{Number: 1317, Code: bserr.Canceled},
+	{Number: 3024, Code: bserr.Timeout},
+	{Number: 1290, Code: bserr.ReadOnlyTransaction},
+	{Number: 1792, Code: bserr.ReadOnlyTransaction},
+	{Number: 1836, Code: bserr.ReadOnlyTransaction},
+	{Number: 1053, Code: bserr.ConnectionLost},
+	{Number: 1927, Code: bserr.ConnectionLost},
+	{Number: 1264, Code: bserr.NumericOverflow},
+	{Number: 1690, Code: bserr.NumericOverflow},
+	{Number: 1406, Code: bserr.StringTooLong},
+	{Number: 1292, Code: bserr.InvalidTextRepresentation},
+	{Number: 1366, Code: bserr.InvalidTextRepresentation},
+	{Number: 9007, Code: bserr.ConcurrentUpdate},   // TiDB: write conflict.
+	{Number: 8002, Code: bserr.ConcurrentUpdate},   // TiDB: SELECT FOR UPDATE write conflict.
+	{Number: 8022, Code: bserr.ConcurrentUpdate},   // TiDB: transaction commit failed, the retry is disabled.
+	{Number: 8028, Code: bserr.ConcurrentUpdate},   // TiDB: information schema is changed during the transaction.
+	{Number: 9001, Code: bserr.Timeout},            // TiDB: PD server timeout.
+	{Number: 9002, Code: bserr.Timeout},            // TiDB: TiKV server timeout.
+	{Number: 9003, Code: bserr.TooManyConnections}, // TiDB: TiKV server is busy.
+	{Number: 9005, Code: bserr.ConnectionLost},     // TiDB: region is unavailable.
+	{SQLState: "22001", Code: bserr.StringTooLong},
+	{SQLState: "22003", Code: bserr.NumericOverflow},
+	{SQLState: "23000", Code: bserr.UniqueViolation},
+	{SQLState: "23502", Code: bserr.NotNullViolation},
+	{SQLState: "23503", Code: bserr.ForeignKeyViolation},
+	{SQLState: "23505", Code: bserr.UniqueViolation},
+	{SQLState: "23514", Code: bserr.CheckViolation},
+	{SQLState: "28000", Code: bserr.AuthenticationFailed},
+	{SQLState: "42000", Code: bserr.InvalidInputSyntax},
+	{SQLState: "42S02", Code: bserr.TableNotFound},
+	{SQLState: "HY000", Code: bserr.InternalError},
+	{SQLState: "HY001", Code: bserr.TooManyConnections},
+	{SQLState: "HYT00", Code: bserr.Timeout},
+	{SQLState: "HYT01", Code: bserr.TooManyConnections},
+	{SQLState: "HYT02", Code: bserr.TooManyConnections},
+	{SQLState: "42102", Code: bserr.TableNotFound},
+	{SQLState: "40001", Code: bserr.ConcurrentUpdate},
+	{SQLState: "08", Code: bserr.ConnectionLost},
+	{SQLState: "22", Code: bserr.DataException},
+	{SQLState: "23", Code: bserr.ConstraintViolation},
+}
+
+// DefaultErrorMappings returns a copy of the default table that maps
+// the MySQL error numbers and SQLSTATE codes to the bserr codes.
+func DefaultErrorMappings() driver.ErrorMappings {
+	return append(driver.ErrorMappings(nil), defaultErrorMappings...)
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlblockysql
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// duplicateKeyRegexp matches the key of the duplicate entry error (1062), i.e.:
+	//  - Duplicate entry '1' for key 'PRIMARY' (MySQL 5.7, MariaDB)
+	//  - Duplicate entry '1' for key 'users.PRIMARY' (MySQL 8.0.19+)
+	duplicateKeyRegexp = regexp.MustCompile(`for key '([^']+)'$`)
+
+	// foreignKeyRegexp matches the foreign key constraint errors (1451, 1452), i.e.:
+	//  Cannot add or update a child row: a foreign key constraint fails
+	//  (`db`.`child`, CONSTRAINT `fk_parent` FOREIGN KEY (`parent_id`) REFERENCES `parent` (`id`))
+	foreignKeyRegexp = regexp.MustCompile("\\((?:`[^`]+`\\.)?`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(([^)]+)\\)")
+
+	// checkConstraintRegexp matches the MySQL check constraint error (3819), i.e.:
+	//  Check constraint 'chk_price' is violated.
+	checkConstraintRegexp = regexp.MustCompile(`^Check constraint '([^']+)' is violated`)
+
+	// mariaDBCheckConstraintRegexp matches the MariaDB check constraint error (4025), i.e.:
+	//  CONSTRAINT `chk_price` failed for `db`.`products`
+	mariaDBCheckConstraintRegexp = regexp.MustCompile("^CONSTRAINT `([^`]+)` failed for (?:`[^`]+`\\.)?`([^`]+)`")
+
+	// columnRegexp matches the column of the errors, i.e.:
+	//  - Column 'name' cannot be null (1048)
+	//  - Field 'name' doesn't have a default value (1364)
+	//  - Data too long for column 'name' at row 1 (1406)
+	//  - Out of range value for column 'price' at row 1 (1264)
+	//  - Incorrect integer value: 'abc' for column 'price' at row 1 (1366)
+	columnRegexp = regexp.MustCompile(`(?:^Column|^Field|for column) '([^']+)'`)
+
+	// tableRegexp matches the table of the table not found error (1146), i.e.:
+	//  Table 'db.users' doesn't exist
+	tableRegexp = regexp.MustCompile(`^Table '([^']+)' doesn't exist`)
+)
+
+// messageDetails contains the names extracted from the error message.
+type messageDetails struct {
+	table      string
+	column     string
+	constraint string
+}
+
+// parseErrorMessage extracts the table, column and constraint names
+// from the message of the error with given number.
+// The messages have a stable format across MySQL 5.7, 8.x and MariaDB.
+func parseErrorMessage(number uint16, msg string) messageDetails {
+	var md messageDetails
+	switch number {
+	case 1062:
+		m := duplicateKeyRegexp.FindStringSubmatch(msg)
+		if m == nil {
+			break
+		}
+		// Since MySQL 8.0.19 the key name is prefixed with the table name.
+		if table, key, ok := strings.Cut(m[1], "."); ok {
+			md.table, md.constraint = table, key
+		} else {
+			md.constraint = m[1]
+		}
+	case 1451, 1452:
+		m := foreignKeyRegexp.FindStringSubmatch(msg)
+		if m == nil {
+			break
+		}
+		md.table, md.constraint = m[1], m[2]
+		// The column is set only for single column foreign keys.
+		if !strings.Contains(m[3], ",") {
+			md.column = strings.Trim(m[3], "`")
+		}
+	case 3819:
+		if m := checkConstraintRegexp.FindStringSubmatch(msg); m != nil {
+			md.constraint = m[1]
+		}
+	case 4025:
+		if m := mariaDBCheckConstraintRegexp.FindStringSubmatch(msg); m != nil {
+			md.constraint, md.table = m[1], m[2]
+		}
+	case 1048, 1364, 1406, 1264, 1366:
+		if m := columnRegexp.FindStringSubmatch(msg); m != nil {
+			md.column = m[1]
+		}
+	case 1146:
+		m := tableRegexp.FindStringSubmatch(msg)
+		if m == nil {
+			break
+		}
+		// The table name is prefixed with the database name.
+		if _, table, ok := strings.Cut(m[1], "."); ok {
+			md.table = table
+		} else {
+			md.table = m[1]
+		}
+	}
+	return md
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlblockysql
+
+import "testing"
+
+func TestParseErrorMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		number uint16
+		msg    string
+		want   messageDetails
+	}{
+		{
+			name:   "duplicate entry mysql 5.7",
+			number: 1062,
+			msg:    "Duplicate entry 'alice@example.com' for key 'users_email_key'",
+			want:   messageDetails{constraint: "users_email_key"},
+		},
+		{
+			name:   "duplicate entry mysql 8.0.19",
+			number: 1062,
+			msg:    "Duplicate entry 'alice@example.com' for key 'users.users_email_key'",
+			want:   messageDetails{table: "users", constraint: "users_email_key"},
+		},
+		{
+			name:   "duplicate primary key mariadb",
+			number: 1062,
+			msg:    "Duplicate entry '1' for key 'PRIMARY'",
+			want:   messageDetails{constraint: "PRIMARY"},
+		},
+		{
+			name:   "parent row",
+			number: 1451,
+			msg:    "Cannot delete or update a parent row: a foreign key constraint fails (`shop`.`orders`, CONSTRAINT `orders_ibfk_1` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`))",
+			want:   messageDetails{table: "orders", column: "customer_id", constraint: "orders_ibfk_1"},
+		},
+		{
+			name:   "child row",
+			number: 1452,
+			msg:    "Cannot add or update a child row: a foreign key constraint fails (`shop`.`orders`, CONSTRAINT `fk_orders_customer` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE CASCADE)",
+			want:   messageDetails{table: "orders", column: "customer_id", constraint: "fk_orders_customer"},
+		},
+		{
+			name:   "child row multi column",
+			number: 1452,
+			msg:    "Cannot add or update a child row: a foreign key constraint fails (`shop`.`order_items`, CONSTRAINT `fk_items_order` FOREIGN KEY (`order_id`, `shop_id`) REFERENCES `orders` (`id`, `shop_id`))",
+			want:   messageDetails{table: "order_items", constraint: "fk_items_order"},
+		},
+		{
+			name:   "child row dotted names",
+			number: 1452,
+			msg:    "Cannot add or update a child row: a foreign key constraint fails (`shop`.`order.items`, CONSTRAINT `fk.items.order` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`))",
+			want:   messageDetails{table: "order.items", column: "order_id", constraint: "fk.items.order"},
+		},
+		{
+			name:   "check constraint mysql",
+			number: 3819,
+			msg:    "Check constraint 'products_chk_1' is violated.",
+			want:   messageDetails{constraint: "products_chk_1"},
+		},
+		{
+			name:   "check constraint mariadb",
+			number: 4025,
+			msg:    "CONSTRAINT `chk_price` failed for `shop`.`products`",
+			want:   messageDetails{table: "products", constraint: "chk_price"},
+		},
+		{
+			name:   "check constraint mariadb dotted names",
+			number: 4025,
+			msg:    "CONSTRAINT `products.price` failed for `shop`.`products.archive`",
+			want:   messageDetails{table: "products.archive", constraint: "products.price"},
+		},
+		{
+			name:   "column cannot be null",
+			number: 1048,
+			msg:    "Column 'email' cannot be null",
+			want:   messageDetails{column: "email"},
+		},
+		{
+			name:   "data too long",
+			number: 1406,
+			msg:    "Data too long for column 'name' at row 1",
+			want:   messageDetails{column: "name"},
+		},
+		{
+			name:   "not matching",
+			number: 1452,
+			msg:    "Cannot add or update a child row: a foreign key constraint fails",
+			want:   messageDetails{},
+		},
+		{
+			name:   "unknown number",
+			number: 1045,
+			msg:    "Access denied for user 'root'@'localhost' (using password: YES)",
+			want:   messageDetails{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseErrorMessage(tt.number, tt.msg); got != tt.want {
+				t.Errorf("parseErrorMessage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ package mysqlblockysql
 import (
 	"context"
 	"database/sql"
+	sqldriver "database/sql/driver"
 	"errors"
 	"fmt"
 	"net/url"
@@ -44,6 +45,16 @@ var _ blockysql.DBURLOpener = (*URLOpener)(nil)
 type URLOpener struct {
 	// TraceOpts contains options for OpenCensus.
 	TraceOpts []ocsql.TraceOption
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	// I.e. {Number: 1644, Message: "access denied", Code: bserr.PermissionDenied}
+	// maps an application error raised by the SIGNAL statement to bserr.PermissionDenied.
+	ErrorMappings driver.ErrorMappings
 }
 
 // OpenDBURL opens a new database connection for the given URL.
@@ -53,12 +64,26 @@ func (o *URLOpener) OpenDBURL(ctx context.Context, u *url.URL) (*blockysql.DB, e
 		return nil, fmt.Errorf("mysqlblockysql: open database failed: %v", err)
 	}
 
-	return OpenDB(ctx, cfg, Options{TraceOpts: o.TraceOpts})
+	return OpenDB(ctx, cfg, Options{
+		TraceOpts:     o.TraceOpts,
+		DBOptions:     o.DBOptions,
+		ErrorMappings: o.ErrorMappings,
+	})
 }
 
 type Options struct {
 	// TraceOpts contains options for OpenCensus.
 	TraceOpts []ocsql.TraceOption
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	// I.e. {Number: 1644, Message: "access denied", Code: bserr.PermissionDenied}
+	// maps an application error raised by the SIGNAL statement to bserr.PermissionDenied.
+	ErrorMappings driver.ErrorMappings
 }
 
 // OpenDB opens a new database connection and returns
@@ -71,17 +96,59 @@ func OpenDB(ctx context.Context, cfg *mysql.Config, opts Options) (*blockysql.DB
 
 	db := sql.OpenDB(ocsql.WrapConnector(c, opts.TraceOpts...))
 
-	d := &DB{db: db}
+	d := &DB{db: db, errorMappings: defaultErrorMappings.With(opts.ErrorMappings...)}
+	bdb, err := blockysql.NewDB(d, opts.DBOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if the database is TiDB or MariaDB.
+	row := db.QueryRowContext(ctx, "SELECT VERSION()")
+	if err = row.Scan(&d.serverInfo.Version); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("mysqlblockysql: open database failed: %w", bdb.TranslateError(err))
+	}
+	d.dialect, d.serverInfo.ServerVersion = parseVersion(d.serverInfo.Version)
 
-	return blockysql.NewDB(d)
+	return bdb, nil
 }
 
-// Compile time check if URLOpener implements blockysql.DBURLOpener.
-var _ driver.DB = (*DB)(nil)
+// parseVersion returns the dialect and the server version
+// of the given result of the SELECT VERSION() query, i.e.:
+//   - 8.0.34 (MySQL)
+//   - 5.5.5-10.11.4-MariaDB-1:10.11.4+maria~ubu2204 (MariaDB)
+//   - 5.7.25-TiDB-v7.1.0 (TiDB)
+func parseVersion(version string) (string, driver.ServerVersion) {
+	if _, tidbVersion, isTiDB := strings.Cut(version, "-TiDB-"); isTiDB {
+		v, _ := driver.ParseServerVersion(tidbVersion)
+		return driver.DialectTiDB, v
+	}
+
+	if isMariaDB := strings.Contains(version, "MariaDB"); isMariaDB {
+		// The replication protocol of MariaDB prefixes the version with 5.5.5.
+		v, _ := driver.ParseServerVersion(strings.TrimPrefix(version, "5.5.5-"))
+		return driver.DialectMariaDB, v
+	}
 
-// DB is the driver for the PostgreSQL database.
+	v, _ := driver.ParseServerVersion(version)
+	return driver.DialectMySQL, v
+}
+
+// Compile time check if DB implements driver.DB and the optional driver interfaces.
+var (
+	_ driver.DB                  = (*DB)(nil)
+	_ driver.ErrorDetailer       = (*DB)(nil)
+	_ driver.ErrorClassifier     = (*DB)(nil)
+	_ driver.ServerInformer      = (*DB)(nil)
+	_ driver.CapabilityDescriber = (*DB)(nil)
+)
+
+// DB is the driver for the MySQL, MariaDB and TiDB databases.
 type DB struct {
-	db *sql.DB
+	db            *sql.DB
+	dialect       string
+	serverInfo    driver.ServerInfo
+	errorMappings driver.ErrorMappings
 }
 
 // DriverName implements driver.DB
@@ -91,95 +158,73 @@ func (d *DB) DriverName() string {
 
 // Dialect implements driver.DB
 func (d *DB) Dialect() string {
-	return driver.DialectMySQL
+	return d.dialect
+}
+
+// ServerInfo implements driver.ServerInformer.
+func (d *DB) ServerInfo() driver.ServerInfo {
+	return d.serverInfo
+}
+
+// Capabilities implements driver.CapabilityDescriber.
+func (d *DB) Capabilities() driver.Capabilities {
+	return driver.DefaultCapabilities(d.dialect, d.serverInfo)
 }
 
 // ErrorCode implements driver.DB.
 func (d *DB) ErrorCode(err error) bserr.Code {
-	if err == nil {
-		return bserr.OK
+	if code, ok := driver.SentinelErrorCode(err); ok {
+		return code
+	}
+
+	details, ok := d.ErrorDetails(err)
+	if !ok {
+		if errors.Is(err, mysql.ErrInvalidConn) {
+			return bserr.ConnectionLost
+		}
+		return bserr.Unknown
 	}
 
-	if err == sql.ErrNoRows {
-		return bserr.NotFound
+	if code, ok := d.errorMappings.Code(details); ok {
+		return code
 	}
+	return bserr.Unknown
+}
+
+// ErrorClass implements driver.ErrorClassifier.
+// The class of the error code is refined with the state
+// of the connection and the transaction after the error.
+func (d *DB) ErrorClass(err error) bserr.Class {
+	class := d.ErrorCode(err).Class()
 
-	if err == sql.ErrTxDone {
-		return bserr.TxDone
+	// The bad connection error is returned only if nothing was sent to the server.
+	if errors.Is(err, sqldriver.ErrBadConn) {
+		return class | bserr.Retryable | bserr.Transient | bserr.SafeToRetry
 	}
 
-	if err == context.DeadlineExceeded {
-		return bserr.Timeout
+	// The connection broke during the request, its outcome is unknown.
+	if errors.Is(err, mysql.ErrInvalidConn) {
+		return class | bserr.Retryable | bserr.Transient
 	}
 
 	var derr *mysql.MySQLError
 	if !errors.As(err, &derr) {
-		return bserr.Unknown
+		return class
 	}
 
 	switch derr.Number {
-	case 1040:
-		return bserr.TooManyConnections
-	case 1045:
-		return bserr.PermissionDenied
-	case 1022, 1062, 1169:
-		return bserr.UniqueViolation
-	case 1064, 1065, 1067, 1072, 1087, 1090:
-		return bserr.InvalidInputSyntax
-	case 1146:
-		return bserr.TableNotFound
-		// Foreign key violation
-	case 1212, 1216, 1217, 1451, 1452, 1453, 1557, 1825, 1826:
-		return bserr.ForeignKeyViolation
-	case 3819, 3820, 3822, 3823:
-		return bserr.ConstraintViolation
-	}
-
-	sqlState := string(derr.SQLState[:])
-	switch sqlState {
-	case "23000":
-		return bserr.UniqueViolation
-	case "23502":
-		return bserr.ConstraintViolation
-	case "23503":
-		return bserr.ForeignKeyViolation
-	case "23505":
-		return bserr.UniqueViolation
-	case "23514":
-		return bserr.ConstraintViolation
-	case "42000":
-		return bserr.InvalidInputSyntax
-	case "42S02":
-		return bserr.TableNotFound
-	case "HY000":
-		return bserr.InternalError
-	case "HY001":
-		return bserr.TooManyConnections
-	case "HYT00":
-		return bserr.Timeout
-	case "HYT01":
-		return bserr.TooManyConnections
-	case "HYT02":
-		return bserr.TooManyConnections
-	case "42102":
-		return bserr.TableNotFound
-	}
-
-	if strings.HasPrefix(sqlState, "22") {
-		return bserr.ConstraintViolation
-	}
-
-	if strings.HasPrefix(sqlState, "23") {
-		return bserr.ConstraintViolation
+	case 1180, 1181: // Got error during COMMIT / ROLLBACK.
+		class |= bserr.Transient | bserr.ServerCaused
 	}
-
-	return bserr.Unknown
+	return class
 }
 
 // HasErrorDetails returns true if the driver supports error details,
 // such as column, table and constraint name.
+// The MySQL errors don't carry the names as separate fields,
+// thus they are extracted from the error message.
 func (d *DB) HasErrorDetails() bool {
-	return false
+	return true
 }
 
 // ErrorColumn returns the column name of the given error
@@ -188,7 +233,11 @@ func (d *DB) ErrorColumn(err error) string {
 	if err == nil {
 		return ""
 	}
-	return ""
+	var derr *mysql.MySQLError
+	if !errors.As(err, &derr) {
+		return ""
+	}
+	return parseErrorMessage(derr.Number, derr.Message).column
 }
 
 // ErrorTable returns the table name of the given error
@@ -197,18 +246,44 @@ func (d *DB) ErrorTable(err error) string {
 	if err == nil {
 		return ""
 	}
-
-	return ""
+	var derr *mysql.MySQLError
+	if !errors.As(err, &derr) {
+		return ""
+	}
+	return parseErrorMessage(derr.Number, derr.Message).table
 }
 
 // ErrorConstraint returns the constraint name of the given error
 // if the driver doesn't support it, it should return an empty string.
+// For the duplicate entry errors it is the name of the violated key.
 func (d *DB) ErrorConstraint(err error) string {
 	if err == nil {
 		return ""
 	}
+	var derr *mysql.MySQLError
+	if !errors.As(err, &derr) {
+		return ""
+	}
+	return parseErrorMessage(derr.Number, derr.Message).constraint
+}
 
-	return ""
+// ErrorDetails implements driver.ErrorDetailer.
+// MySQL provides only the error number, SQLSTATE and the message,
+// the table, column and constraint names are extracted from the message.
+func (d *DB) ErrorDetails(err error) (driver.ErrorDetails, bool) {
+	var derr *mysql.MySQLError
+	if !errors.As(err, &derr) {
+		return driver.ErrorDetails{}, false
+	}
+	md := parseErrorMessage(derr.Number, derr.Message)
+	return driver.ErrorDetails{
+		SQLState:   string(derr.SQLState[:]),
+		Number:     int(derr.Number),
+		Message:    derr.Message,
+		Table:      md.table,
+		Column:     md.column,
+		Constraint: md.constraint,
+	}, true
 }
 
 // DB returns the underlying database/sql.DB.
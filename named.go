@@ -0,0 +1,518 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+// ErrMissingNamedArg is returned when no value is provided
+// for a named parameter of the query.
+var ErrMissingNamedArg = errors.New("blockysql: missing value for the named parameter")
+
+// BindNamed converts the named parameters of the query into the positional
+// placeholders of the given capabilities, and returns the args in their order, i.e.
+// for the driver.PlaceholderDollar:
+//
+//	SELECT * FROM users WHERE id = :id AND tenant = :tenant
+//
+// becomes:
+//
+//	SELECT * FROM users WHERE id = $1 AND tenant = $2
+//
+// The args could be either a single map with string keys, a single struct
+// (or a pointer to it) or any number of the sql.NamedArg values.
+// The struct fields are named by their `db` tag, or by their lower-cased name
+// if the tag is not set. The fields tagged with `db:"-"` are ignored.
+//
+// A name repeated in the query reuses the same placeholder for the numbered
+// styles, and repeats the arg for the driver.PlaceholderQuestion.
+// The names within the string literals, quoted identifiers and comments,
+// as well as the postgres "::" casts are left untouched. The "\:" is replaced
+// with a single colon, that doesn't start a named parameter.
+func BindNamed(caps driver.Capabilities, query string, args ...any) (string, []any, error) {
+	nq := compileNamed(caps, query)
+	bound, err := nq.bind(args)
+	if err != nil {
+		return "", nil, err
+	}
+	return nq.query, bound, nil
+}
+
+// namedQuery is a query with the named parameters
+// converted into the positional placeholders.
+type namedQuery struct {
+	query string
+
+	// names are the names of the positional args in their order.
+	names []string
+}
+
+// compileNamed converts the named parameters of the query
+// into the positional placeholders of the given capabilities.
+func compileNamed(caps driver.Capabilities, query string) namedQuery {
+	if !strings.Contains(query, ":") {
+		return namedQuery{query: query}
+	}
+
+	var (
+		sb      strings.Builder
+		names   []string
+		numbers map[string]int
+		style   = caps.Placeholder
+		l       = newLexer(caps)
+	)
+	if style != driver.PlaceholderQuestion {
+		numbers = make(map[string]int)
+	}
+	sb.Grow(len(query))
+	for i := 0; i < len(query); {
+		if end, ok := l.skipNonCode(query, i); ok {
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		if c == '\\' && i+1 < len(query) && query[i+1] == ':' {
+			sb.WriteByte(':')
+			i += 2
+			continue
+		}
+
+		if c != ':' || i+1 == len(query) || !isIdentChar(query[i+1]) || isDigit(query[i+1]) {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j < len(query) && isIdentChar(query[j]) {
+			j++
+		}
+		name := query[i+1 : j]
+		i = j
+
+		if numbers == nil {
+			names = append(names, name)
+			writePlaceholder(&sb, style, len(names))
+			continue
+		}
+
+		n, ok := numbers[name]
+		if !ok {
+			names = append(names, name)
+			n = len(names)
+			numbers[name] = n
+		}
+		writePlaceholder(&sb, style, n)
+	}
+	return namedQuery{query: sb.String(), names: names}
+}
+
+// bind returns the positional args of the query taken from the named args.
+func (nq namedQuery) bind(args []any) ([]any, error) {
+	lookup, err := namedLookup(args)
+	if err != nil {
+		return nil, err
+	}
+
+	bound := make([]any, len(nq.names))
+	for i, name := range nq.names {
+		v, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrMissingNamedArg, name)
+		}
+		bound[i] = v
+	}
+	return bound, nil
+}
+
+// namedLookup returns the function that finds the value of the named parameter in the args.
+func namedLookup(args []any) (func(name string) (any, bool), error) {
+	if len(args) == 0 {
+		return func(string) (any, bool) { return nil, false }, nil
+	}
+
+	if _, ok := args[0].(sql.NamedArg); ok {
+		m := make(map[string]any, len(args))
+		for _, arg := range args {
+			na, ok := arg.(sql.NamedArg)
+			if !ok {
+				return nil, fmt.Errorf("blockysql: named args mixed with the %T arg", arg)
+			}
+			m[na.Name] = na.Value
+		}
+		return mapLookup(m), nil
+	}
+
+	if len(args) > 1 {
+		return nil, fmt.Errorf("blockysql: expected a single map or struct named arg, got %d args", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case map[string]any:
+		return mapLookup(arg), nil
+	case nil:
+		return nil, errors.New("blockysql: named arg is nil")
+	}
+
+	v := reflect.ValueOf(args[0])
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("blockysql: named arg is a nil %s", v.Type())
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("blockysql: named arg map key must be a string, got %s", v.Type().Key())
+		}
+		return func(name string) (any, bool) {
+			mv := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !mv.IsValid() {
+				return nil, false
+			}
+			return mv.Interface(), true
+		}, nil
+	case reflect.Struct:
+		fields := structFields(v.Type())
+		return func(name string) (any, bool) {
+			index, ok := fields[name]
+			if !ok {
+				return nil, false
+			}
+			fv, err := v.FieldByIndexErr(index)
+			if err != nil {
+				// The field is embedded through a nil pointer.
+				return nil, true
+			}
+			return fv.Interface(), true
+		}, nil
+	default:
+		return nil, fmt.Errorf("blockysql: unsupported named arg type %T", args[0])
+	}
+}
+
+// mapLookup returns the lookup function over the map.
+func mapLookup(m map[string]any) func(name string) (any, bool) {
+	return func(name string) (any, bool) {
+		v, ok := m[name]
+		return v, ok
+	}
+}
+
+// structFieldsCache caches the named fields of the struct types.
+var structFieldsCache sync.Map // map[reflect.Type]map[string][]int
+
+// structFields returns the indexes of the named fields of the struct type.
+func structFields(t reflect.Type) map[string][]int {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	collectStructFields(t, fields)
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// collectStructFields collects the named fields of the struct type into the fields map.
+// The fields of the embedded structs without the `db` tag are promoted.
+// Like in the encoding/json, the embedded structs are resolved by depth,
+// so that a shallower field takes precedence over the deeper ones,
+// and the fields of the same name at the same depth are ambiguous and dropped.
+func collectStructFields(t reflect.Type, fields map[string][]int) {
+	type embeddedStruct struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var (
+		next      = []embeddedStruct{{typ: t}}
+		visited   = make(map[reflect.Type]bool)
+		ambiguous = make(map[string]bool)
+	)
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		level := make(map[string][]int)
+		for _, es := range current {
+			if visited[es.typ] {
+				continue
+			}
+			for i := 0; i < es.typ.NumField(); i++ {
+				f := es.typ.Field(i)
+				tag, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+				if tag == "-" {
+					continue
+				}
+
+				index := append(append([]int(nil), es.index...), i)
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if f.Anonymous && tag == "" && ft.Kind() == reflect.Struct {
+					next = append(next, embeddedStruct{typ: ft, index: index})
+					continue
+				}
+				if !f.IsExported() {
+					continue
+				}
+
+				name := tag
+				if name == "" {
+					name = strings.ToLower(f.Name)
+				}
+				if _, ok := fields[name]; ok || ambiguous[name] {
+					continue
+				}
+				if _, ok := level[name]; ok {
+					ambiguous[name] = true
+					continue
+				}
+				level[name] = index
+			}
+		}
+
+		for _, es := range current {
+			visited[es.typ] = true
+		}
+		for name, index := range level {
+			if !ambiguous[name] {
+				fields[name] = index
+			}
+		}
+	}
+}
+
+// BindNamed converts the named parameters of the query into the placeholder
+// style of the connected database, and returns the args in their order.
+// See the BindNamed function for details.
+func (d *DB) BindNamed(query string, args ...any) (string, []any, error) {
+	return BindNamed(d.Capabilities(), query, args...)
+}
+
+// NamedExecContext executes a query with the named parameters without returning any rows.
+// See the BindNamed function for the supported args.
+func (d *DB) NamedExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	q, bound, err := d.BindNamed(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.db.ExecContext(ctx, q, bound...)
+	return res, d.wrapErr(err)
+}
+
+// NamedQueryContext executes a query with the named parameters that returns rows, typically a SELECT.
+// See the BindNamed function for the supported args.
+func (d *DB) NamedQueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	q, bound, err := d.BindNamed(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.db.QueryContext(ctx, q, bound...)
+	return rows, d.wrapErr(err)
+}
+
+// NamedQueryRowContext executes a query with the named parameters,
+// that is expected to return at most one row.
+// NamedQueryRowContext always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called.
+func (d *DB) NamedQueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	q, bound, err := d.BindNamed(query, args...)
+	if err != nil {
+		return &Row{db: d, err: err}
+	}
+	return &Row{db: d, row: d.db.QueryRowContext(ctx, q, bound...)}
+}
+
+// PrepareNamedContext creates a prepared statement with the named parameters
+// for later queries or executions.
+// The caller must call the statement's Close method when the statement is no longer needed.
+func (d *DB) PrepareNamedContext(ctx context.Context, query string) (*NamedStmt, error) {
+	nq := compileNamed(d.Capabilities(), query)
+	stmt, err := d.db.PrepareContext(ctx, nq.query)
+	if err != nil {
+		return nil, d.wrapErr(err)
+	}
+	return &NamedStmt{db: d, stmt: stmt, names: nq.names}, nil
+}
+
+// NamedExecContext executes a query with the named parameters without returning any rows.
+// See the BindNamed function for the supported args.
+func (t *Tx) NamedExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	q, bound, err := t.db.BindNamed(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.tx.ExecContext(ctx, q, bound...)
+	return res, t.db.wrapErr(err)
+}
+
+// NamedQueryContext executes a query with the named parameters that returns rows, typically a SELECT.
+// See the BindNamed function for the supported args.
+func (t *Tx) NamedQueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	q, bound, err := t.db.BindNamed(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := t.tx.QueryContext(ctx, q, bound...)
+	return rows, t.db.wrapErr(err)
+}
+
+// NamedQueryRowContext executes a query with the named parameters,
+// that is expected to return at most one row.
+// NamedQueryRowContext always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called.
+func (t *Tx) NamedQueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	q, bound, err := t.db.BindNamed(query, args...)
+	if err != nil {
+		return &Row{db: t.db, err: err}
+	}
+	return &Row{db: t.db, row: t.tx.QueryRowContext(ctx, q, bound...)}
+}
+
+// PrepareNamedContext creates a prepared statement with the named parameters
+// for use within a transaction.
+// The returned statement operates within the transaction and will be closed
+// when the transaction has been committed or rolled back.
+func (t *Tx) PrepareNamedContext(ctx context.Context, query string) (*NamedStmt, error) {
+	nq := compileNamed(t.db.Capabilities(), query)
+	stmt, err := t.tx.PrepareContext(ctx, nq.query)
+	if err != nil {
+		return nil, t.db.wrapErr(err)
+	}
+	return &NamedStmt{db: t.db, stmt: stmt, names: nq.names}, nil
+}
+
+// NamedStmtContext returns a transaction-specific named statement
+// from an existing named statement.
+func (t *Tx) NamedStmtContext(ctx context.Context, stmt *NamedStmt) *NamedStmt {
+	return &NamedStmt{db: t.db, stmt: t.tx.StmtContext(ctx, stmt.stmt), names: stmt.names}
+}
+
+// NamedExecContext executes a query with the named parameters without returning any rows.
+// See the BindNamed function for the supported args.
+func (c *Conn) NamedExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	q, bound, err := c.db.BindNamed(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.conn.ExecContext(ctx, q, bound...)
+	return res, c.db.wrapErr(err)
+}
+
+// NamedQueryContext executes a query with the named parameters that returns rows, typically a SELECT.
+// See the BindNamed function for the supported args.
+func (c *Conn) NamedQueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	q, bound, err := c.db.BindNamed(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := c.conn.QueryContext(ctx, q, bound...)
+	return rows, c.db.wrapErr(err)
+}
+
+// NamedQueryRowContext executes a query with the named parameters,
+// that is expected to return at most one row.
+// NamedQueryRowContext always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called.
+func (c *Conn) NamedQueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	q, bound, err := c.db.BindNamed(query, args...)
+	if err != nil {
+		return &Row{db: c.db, err: err}
+	}
+	return &Row{db: c.db, row: c.conn.QueryRowContext(ctx, q, bound...)}
+}
+
+// PrepareNamedContext creates a prepared statement with the named parameters
+// for later queries or executions on the connection.
+// The caller must call the statement's Close method when the statement is no longer needed.
+func (c *Conn) PrepareNamedContext(ctx context.Context, query string) (*NamedStmt, error) {
+	nq := compileNamed(c.db.Capabilities(), query)
+	stmt, err := c.conn.PrepareContext(ctx, nq.query)
+	if err != nil {
+		return nil, c.db.wrapErr(err)
+	}
+	return &NamedStmt{db: c.db, stmt: stmt, names: nq.names}, nil
+}
+
+// NamedStmt is a prepared statement with the named parameters.
+// It is a wrapper over the database/sql.Stmt, that binds the named args
+// into the positional placeholders of the prepared query.
+type NamedStmt struct {
+	db    *DB
+	stmt  *sql.Stmt
+	names []string
+}
+
+// ExecContext executes the prepared statement with the named args
+// and returns a sql.Result summarizing the effect of the statement.
+// See the BindNamed function for the supported args.
+func (s *NamedStmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	bound, err := namedQuery{names: s.names}.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.stmt.ExecContext(ctx, bound...)
+	return res, s.db.wrapErr(err)
+}
+
+// QueryContext executes the prepared query statement with the named args
+// and returns the query results as a *sql.Rows.
+// See the BindNamed function for the supported args.
+func (s *NamedStmt) QueryContext(ctx context.Context, args ...any) (*sql.Rows, error) {
+	bound, err := namedQuery{names: s.names}.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.stmt.QueryContext(ctx, bound...)
+	return rows, s.db.wrapErr(err)
+}
+
+// QueryRowContext executes the prepared query statement with the named args,
+// that is expected to return at most one row.
+// QueryRowContext always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called.
+func (s *NamedStmt) QueryRowContext(ctx context.Context, args ...any) *Row {
+	bound, err := namedQuery{names: s.names}.bind(args)
+	if err != nil {
+		return &Row{db: s.db, err: err}
+	}
+	return &Row{db: s.db, row: s.stmt.QueryRowContext(ctx, bound...)}
+}
+
+// Close closes the statement.
+func (s *NamedStmt) Close() error {
+	return s.db.wrapErr(s.stmt.Close())
+}
+
+// Stmt returns the underlying database/sql.Stmt.
+func (s *NamedStmt) Stmt() *sql.Stmt {
+	return s.stmt
+}
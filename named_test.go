@@ -0,0 +1,196 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+type namedTenant struct {
+	Tenant string `db:"tenant"`
+}
+
+type namedUser struct {
+	*namedTenant
+	ID     int64 `db:"id"`
+	Name   string
+	Secret string `db:"-"`
+}
+
+type namedRecord struct {
+	ID int64 `db:"id"`
+}
+
+type namedAudit struct {
+	namedRecord
+	Tenant string `db:"tenant"`
+}
+
+type namedKey struct {
+	ID int64 `db:"id"`
+}
+
+type namedLabel struct {
+	Name string `db:"name"`
+}
+
+type namedTitle struct {
+	Name string `db:"name"`
+}
+
+// namedEntry has the deeper id of the namedRecord shadowed by the namedKey,
+// and the ambiguous name of the namedLabel and namedTitle.
+type namedEntry struct {
+	namedAudit
+	*namedKey
+	namedLabel
+	namedTitle
+}
+
+func TestBindNamed(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialect   string
+		query     string
+		args      []any
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "map",
+			dialect:   driver.DialectPostgres,
+			query:     "SELECT * FROM users WHERE id = :id AND tenant = :tenant",
+			args:      []any{map[string]any{"id": 1, "tenant": "acme"}},
+			wantQuery: "SELECT * FROM users WHERE id = $1 AND tenant = $2",
+			wantArgs:  []any{1, "acme"},
+		},
+		{
+			name:      "struct",
+			dialect:   driver.DialectMSSQL,
+			query:     "UPDATE users SET name = :name WHERE id = :id AND tenant = :tenant",
+			args:      []any{&namedUser{namedTenant: &namedTenant{Tenant: "acme"}, ID: 1, Name: "John"}},
+			wantQuery: "UPDATE users SET name = @p1 WHERE id = @p2 AND tenant = @p3",
+			wantArgs:  []any{"John", int64(1), "acme"},
+		},
+		{
+			name:      "struct with nil embedded",
+			dialect:   driver.DialectOracle,
+			query:     "SELECT * FROM users WHERE id = :id AND tenant = :tenant",
+			args:      []any{namedUser{ID: 1}},
+			wantQuery: "SELECT * FROM users WHERE id = :1 AND tenant = :2",
+			wantArgs:  []any{int64(1), nil},
+		},
+		{
+			name:      "struct with conflicting embedded",
+			dialect:   driver.DialectPostgres,
+			query:     "SELECT * FROM entries WHERE id = :id AND tenant = :tenant",
+			args:      []any{namedEntry{namedAudit: namedAudit{namedRecord: namedRecord{ID: 2}, Tenant: "acme"}, namedKey: &namedKey{ID: 1}}},
+			wantQuery: "SELECT * FROM entries WHERE id = $1 AND tenant = $2",
+			wantArgs:  []any{int64(1), "acme"},
+		},
+		{
+			name:      "sql named args",
+			dialect:   driver.DialectPostgres,
+			query:     "SELECT * FROM users WHERE id = :id",
+			args:      []any{sql.Named("id", 1)},
+			wantQuery: "SELECT * FROM users WHERE id = $1",
+			wantArgs:  []any{1},
+		},
+		{
+			name:      "repeated numbered",
+			dialect:   driver.DialectPostgres,
+			query:     "SELECT * FROM t WHERE a = :x OR b = :x",
+			args:      []any{map[string]any{"x": 1}},
+			wantQuery: "SELECT * FROM t WHERE a = $1 OR b = $1",
+			wantArgs:  []any{1},
+		},
+		{
+			name:      "repeated question",
+			dialect:   driver.DialectMySQL,
+			query:     "SELECT * FROM t WHERE a = :x OR b = :x",
+			args:      []any{map[string]any{"x": 1}},
+			wantQuery: "SELECT * FROM t WHERE a = ? OR b = ?",
+			wantArgs:  []any{1, 1},
+		},
+		{
+			name:      "casts, escapes and literals",
+			dialect:   driver.DialectPostgres,
+			query:     "SELECT :x::text, ':y', \\:z, arr[1:2] -- :c\nFROM t",
+			args:      []any{map[string]any{"x": 1}},
+			wantQuery: "SELECT $1::text, ':y', :z, arr[1:2] -- :c\nFROM t",
+			wantArgs:  []any{1},
+		},
+		{
+			name:      "mysql backslash escapes",
+			dialect:   driver.DialectMySQL,
+			query:     "SELECT * FROM t WHERE s = 'it\\'s :x' AND id = :id # :c",
+			args:      []any{map[string]any{"id": 1}},
+			wantQuery: "SELECT * FROM t WHERE s = 'it\\'s :x' AND id = ? # :c",
+			wantArgs:  []any{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := BindNamed(capsOf(tt.dialect), tt.query, tt.args...)
+			if err != nil {
+				t.Fatalf("BindNamed() error = %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("BindNamed() query = %q, want %q", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("BindNamed() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBindNamedErrors(t *testing.T) {
+	caps := capsOf(driver.DialectPostgres)
+	query := "SELECT * FROM users WHERE id = :id AND tenant = :tenant"
+
+	_, _, err := BindNamed(caps, query, map[string]any{"id": 1})
+	if !errors.Is(err, ErrMissingNamedArg) {
+		t.Errorf("BindNamed() missing arg error = %v, want %v", err, ErrMissingNamedArg)
+	}
+
+	_, _, err = BindNamed(caps, "SELECT * FROM entries WHERE name = :name", namedEntry{})
+	if !errors.Is(err, ErrMissingNamedArg) {
+		t.Errorf("BindNamed() ambiguous field error = %v, want %v", err, ErrMissingNamedArg)
+	}
+
+	tests := []struct {
+		name string
+		args []any
+	}{
+		{name: "multiple args", args: []any{1, 2}},
+		{name: "mixed named args", args: []any{sql.Named("id", 1), 2}},
+		{name: "nil", args: []any{nil}},
+		{name: "unsupported type", args: []any{1}},
+		{name: "non-string map key", args: []any{map[int]any{1: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := BindNamed(caps, query, tt.args...); err == nil {
+				t.Error("BindNamed() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+// Option is an option that configures the DB.
+type Option func(d *DB)
+
+// WithErrorTranslation makes the DB, along with its transactions and connections,
+// return errors already translated into the *bserr.Error.
+// The translated errors still unwrap to the original driver errors.
+// The errors deferred to the *sql.Row, *sql.Rows and *sql.Stmt are translated
+// by their wrappers returned from the WrapRow, WrapRows and WrapStmt.
+func WithErrorTranslation() Option {
+	return func(d *DB) {
+		d.translateErrors = true
+	}
+}
+
+// WithRebind makes the DB, along with its transactions and connections,
+// convert the question mark placeholders of the queries into the placeholder
+// style of the connected database before executing them.
+// This way the same queries could be run on any of the supported databases,
+// i.e. "WHERE id = ?" is executed as "WHERE id = $1" on postgres.
+// See the Rebind function for details.
+func WithRebind() Option {
+	return func(d *DB) {
+		d.rebind = true
+	}
+}
+
+// wrapErr translates the given error if the error translation is enabled.
+func (d *DB) wrapErr(err error) error {
+	if err == nil || !d.translateErrors {
+		return err
+	}
+	return d.TranslateError(err)
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleblockysql
+
+import (
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// defaultErrorMappings maps the Oracle error numbers (ORA-NNNNN) to the bserr codes.
+// See https://docs.oracle.com/en/database/oracle/oracle-database/19/errmg/.
+var defaultErrorMappings = driver.ErrorMappings{
+	{Number: 1, Code: bserr.UniqueViolation},              // ORA-00001: unique constraint violated.
+	{Number: 2291, Code: bserr.ForeignKeyViolation},       // ORA-02291: integrity constraint violated - parent key not found.
+	{Number: 2292, Code: bserr.ForeignKeyViolation},       // ORA-02292: integrity constraint violated - child record found.
+	{Number: 1400, Code: bserr.NotNullViolation},          // ORA-01400: cannot insert NULL.
+	{Number: 1407, Code: bserr.NotNullViolation},          // ORA-01407: cannot update to NULL.
+	{Number: 2290, Code: bserr.CheckViolation},            // ORA-02290: check constraint violated.
+	{Number: 12899, Code: bserr.StringTooLong},            // ORA-12899: value too large for column.
+	{Number: 1438, Code: bserr.NumericOverflow},           // ORA-01438: value larger than specified precision.
+	{Number: 1722, Code: bserr.InvalidTextRepresentation}, // ORA-01722: invalid number.
+	{Number: 1858, Code: bserr.InvalidTextRepresentation}, // ORA-01858: a non-numeric character was found where a numeric was expected.
+	{Number: 1861, Code: bserr.InvalidTextRepresentation}, // ORA-01861: literal does not match format string.
+	{Number: 1476, Code: bserr.DataException},             // ORA-01476: divisor is equal to zero.
+	{Number: 1403, Code: bserr.NotFound},                  // ORA-01403: no data found.
+	{Number: 942, Code: bserr.TableNotFound},              // ORA-00942: table or view does not exist.
+	{Number: 900, Code: bserr.InvalidInputSyntax},         // ORA-00900: invalid SQL statement.
+	{Number: 904, Code: bserr.InvalidInputSyntax},         // ORA-00904: invalid identifier.
+	{Number: 933, Code: bserr.InvalidInputSyntax},         // ORA-00933: SQL command not properly ended.
+	{Number: 936, Code: bserr.InvalidInputSyntax},         // ORA-00936: missing expression.
+	{Number: 60, Code: bserr.Deadlock},                    // ORA-00060: deadlock detected while waiting for resource.
+	{Number: 54, Code: bserr.LockNotAvailable},            // ORA-00054: resource busy and acquire with NOWAIT specified.
+	{Number: 30006, Code: bserr.LockNotAvailable},         // ORA-30006: resource busy; acquire with WAIT timeout expired.
+	{Number: 8177, Code: bserr.ConcurrentUpdate},          // ORA-08177: can't serialize access for this transaction.
+	{Number: 1017, Code: bserr.AuthenticationFailed},      // ORA-01017: invalid username/password; logon denied.
+	{Number: 28000, Code: bserr.AuthenticationFailed},     // ORA-28000: the account is locked.
+	{Number: 28001, Code: bserr.AuthenticationFailed},     // ORA-28001: the password has expired.
+	{Number: 1031, Code: bserr.PermissionDenied},          // ORA-01031: insufficient privileges.
+	{Number: 16000, Code: bserr.ReadOnlyTransaction},      // ORA-16000: database open for read-only access.
+	{Number: 1456, Code: bserr.ReadOnlyTransaction},       // ORA-01456: may not perform insert/delete/update operation inside a READ ONLY transaction.
+	{Number: 1013, Code: bserr.Canceled},                  // ORA-01013: user requested cancel of current operation.
+	{Number: 18, Code: bserr.TooManyConnections},          // ORA-00018: maximum number of sessions exceeded.
+	{Number: 20, Code: bserr.TooManyConnections},          // ORA-00020: maximum number of processes exceeded.
+	{Number: 12516, Code: bserr.TooManyConnections},       // ORA-12516: TNS:listener could not find available handler.
+	{Number: 12519, Code: bserr.TooManyConnections},       // ORA-12519: TNS:no appropriate service handler found.
+	{Number: 3113, Code: bserr.ConnectionLost},            // ORA-03113: end-of-file on communication channel.
+	{Number: 3114, Code: bserr.ConnectionLost},            // ORA-03114: not connected to ORACLE.
+	{Number: 3135, Code: bserr.ConnectionLost},            // ORA-03135: connection lost contact.
+	{Number: 12514, Code: bserr.ConnectionLost},           // ORA-12514: TNS:listener does not currently know of service.
+	{Number: 12541, Code: bserr.ConnectionLost},           // ORA-12541: TNS:no listener.
+	{Number: 12170, Code: bserr.Timeout},                  // ORA-12170: TNS:connect timeout occurred.
+	{Number: 4030, Code: bserr.OutOfMemory},               // ORA-04030: out of process memory.
+	{Number: 4031, Code: bserr.OutOfMemory},               // ORA-04031: unable to allocate bytes of shared memory.
+	{Number: 1536, Code: bserr.OutOfDisk},                 // ORA-01536: space quota exceeded for tablespace.
+	{Number: 1653, Code: bserr.OutOfDisk},                 // ORA-01653: unable to extend table.
+	{Number: 1654, Code: bserr.OutOfDisk},                 // ORA-01654: unable to extend index.
+	{Number: 600, Code: bserr.InternalError},              // ORA-00600: internal error code.
+	{Number: 7445, Code: bserr.InternalError},             // ORA-07445: exception encountered: core dump.
+}
+
+// DefaultErrorMappings returns a copy of the default table
+// that maps the Oracle error numbers to the bserr codes.
+func DefaultErrorMappings() driver.ErrorMappings {
+	return append(driver.ErrorMappings(nil), defaultErrorMappings...)
+}
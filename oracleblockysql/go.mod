@@ -0,0 +1,31 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+module github.com/blockysource/blockysql/oracleblockysql
+
+go 1.20
+
+replace github.com/blockysource/blockysql => ../
+
+require (
+	contrib.go.opencensus.io/integrations/ocsql v0.1.7
+	github.com/blockysource/blockysql v0.0.0-00010101000000-000000000000
+	github.com/sijms/go-ora/v2 v2.7.17
+)
+
+require (
+	github.com/blockysource/go-pkg v0.0.0-20230805231140-395364a61d81 // indirect
+	github.com/golang/groupcache v0.0.0-20200121045136-8c9f03a8e57e // indirect
+	go.opencensus.io v0.24.0 // indirect
+)
@@ -0,0 +1,58 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleblockysql
+
+import (
+	"regexp"
+)
+
+var (
+	// constraintRegexp matches the schema and name of the constraint, i.e.:
+	//  - ORA-00001: unique constraint (SHOP.UQ_USERS_EMAIL) violated
+	//  - ORA-02291: integrity constraint (SHOP.FK_ORDERS_USERS) violated - parent key not found
+	//  - ORA-02290: check constraint (SHOP.CK_PRICE) violated
+	constraintRegexp = regexp.MustCompile(`constraint \(([^.()]+)\.([^()]+)\)`)
+
+	// columnRegexp matches the schema, table and column names, i.e.:
+	//  - ORA-01400: cannot insert NULL into ("SHOP"."USERS"."NAME")
+	//  - ORA-12899: value too large for column "SHOP"."USERS"."NAME" (actual: 20, maximum: 10)
+	columnRegexp = regexp.MustCompile(`"([^"]+)"\."([^"]+)"\."([^"]+)"`)
+)
+
+// messageDetails contains the names extracted from the error message.
+type messageDetails struct {
+	schema     string
+	table      string
+	column     string
+	constraint string
+}
+
+// parseErrorMessage extracts the schema, table, column and constraint names
+// from the message of the error with given number.
+// The constraint errors don't report the table nor the column names.
+func parseErrorMessage(number int, msg string) messageDetails {
+	var md messageDetails
+	switch number {
+	case 1, 2290, 2291, 2292:
+		if m := constraintRegexp.FindStringSubmatch(msg); m != nil {
+			md.schema, md.constraint = m[1], m[2]
+		}
+	case 1400, 1407, 12899:
+		if m := columnRegexp.FindStringSubmatch(msg); m != nil {
+			md.schema, md.table, md.column = m[1], m[2], m[3]
+		}
+	}
+	return md
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgxblockysql
+
+import (
+	"github.com/blockysource/blockysql/driver"
+	"github.com/blockysource/blockysql/internal/pgshared"
+)
+
+// defaultErrorMappings maps the SQLSTATE codes to the bserr codes.
+var defaultErrorMappings = pgshared.ErrorMappings()
+
+// DefaultErrorMappings returns a copy of the default table
+// that maps the SQLSTATE codes to the bserr codes.
+func DefaultErrorMappings() driver.ErrorMappings {
+	return append(driver.ErrorMappings(nil), defaultErrorMappings...)
+}
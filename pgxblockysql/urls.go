@@ -30,6 +30,7 @@ import (
 	"github.com/blockysource/blockysql"
 	"github.com/blockysource/blockysql/bserr"
 	"github.com/blockysource/blockysql/driver"
+	"github.com/blockysource/blockysql/internal/pgshared"
 )
 
 func init() {
@@ -55,6 +56,16 @@ type URLOpener struct {
 
 	// OpenDBOpts contains options for the pgx driver.
 	OpenDBOpts []stdlib.OptionOpenDB
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	// I.e. {SQLState: "P0001", Message: "access denied", Code: bserr.PermissionDenied}
+	// maps an application error raised by a function to bserr.PermissionDenied.
+	ErrorMappings driver.ErrorMappings
 }
 
 // OpenDBURL opens a new database connection for the given URL.
@@ -82,8 +93,10 @@ func (o *URLOpener) OpenDBURL(ctx context.Context, u *url.URL) (*blockysql.DB, e
 
 	// Open database.
 	return OpenDB(ctx, *cfg, Options{
-		TraceOpts:  o.TraceOpts,
-		OpenDBOpts: o.OpenDBOpts,
+		TraceOpts:     o.TraceOpts,
+		OpenDBOpts:    o.OpenDBOpts,
+		DBOptions:     o.DBOptions,
+		ErrorMappings: o.ErrorMappings,
 	})
 }
 
@@ -94,10 +107,27 @@ type Options struct {
 
 	// OpenDBOpts contains options for configuring the database connection.
 	OpenDBOpts []stdlib.OptionOpenDB
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	// I.e. {SQLState: "P0001", Message: "access denied", Code: bserr.PermissionDenied}
+	// maps an application error raised by a function to bserr.PermissionDenied.
+	ErrorMappings driver.ErrorMappings
 }
 
-// Compile time check if URLOpener implements blockysql.DBURLOpener.
-var _ driver.DB = (*DB)(nil)
+// Compile time check if DB implements driver.DB and the optional driver interfaces.
+var (
+	_ driver.DB                  = (*DB)(nil)
+	_ driver.ErrorDetailer       = (*DB)(nil)
+	_ driver.ErrorClassifier     = (*DB)(nil)
+	_ driver.ServerInformer      = (*DB)(nil)
+	_ driver.CapabilityDescriber = (*DB)(nil)
+	_ driver.ArrayBinder         = (*DB)(nil)
+)
 
 // OpenDB returns a new bsql.DB backed by a *sql.DB.
 func OpenDB(ctx context.Context, c pgx.ConnConfig, opts Options) (*blockysql.DB, error) {
@@ -107,29 +137,30 @@ func OpenDB(ctx context.Context, c pgx.ConnConfig, opts Options) (*blockysql.DB,
 		opts.TraceOpts...),
 	)
 
-	// Check if the database is CockroachDB.
-	row := db.QueryRowContext(ctx, "SELECT VERSION()")
-	var version string
-	if err := row.Scan(&version); err != nil {
-		return nil, fmt.Errorf("pgxblockysql: open database failed: %v", err)
+	d := &DB{db: db, errorMappings: defaultErrorMappings.With(opts.ErrorMappings...)}
+	bdb, err := blockysql.NewDB(d, opts.DBOptions...)
+	if err != nil {
+		return nil, err
 	}
 
-	d := &DB{db: db}
-	if isCockroach := strings.Contains(version, "CockroachDB"); isCockroach {
-		d.dialect = driver.DialectCockroach
-	} else if isYugaByte := strings.Contains(version, "-YB-"); isYugaByte {
-		d.dialect = driver.DialectYugabyte
-	} else {
-		d.dialect = driver.DialectPostgres
+	// Check the dialect and flavor of the database.
+	// The errors of the probe are the first to reveal the connection
+	// and authentication failures, thus they are translated to carry their codes.
+	d.dialect, d.serverInfo, err = pgshared.ProbeServer(ctx, db)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("pgxblockysql: open database failed: %w", bdb.TranslateError(err))
 	}
 
-	return blockysql.NewDB(d)
+	return bdb, nil
 }
 
 // DB is the driver for the PostgreSQL database.
 type DB struct {
-	db      *sql.DB
-	dialect string
+	db            *sql.DB
+	dialect       string
+	serverInfo    driver.ServerInfo
+	errorMappings driver.ErrorMappings
 }
 
 // DriverName implements driver.DB
@@ -142,79 +173,67 @@ func (d *DB) Dialect() string {
 	return d.dialect
 }
 
-// ErrorCode implements driver.DB.
-func (d *DB) ErrorCode(err error) bserr.Code {
-	if err == nil {
-		return bserr.OK
-	}
+// ServerInfo implements driver.ServerInformer.
+// The flavors of the postgres dialect are driver.FlavorAurora, driver.FlavorRedshift,
+// driver.FlavorAlloyDB and driver.FlavorNeon.
+func (d *DB) ServerInfo() driver.ServerInfo {
+	return d.serverInfo
+}
 
-	if err == sql.ErrNoRows {
-		return bserr.NotFound
-	}
+// Capabilities implements driver.CapabilityDescriber.
+func (d *DB) Capabilities() driver.Capabilities {
+	return driver.DefaultCapabilities(d.dialect, d.serverInfo)
+}
 
-	if err == sql.ErrTxDone {
-		return bserr.TxDone
-	}
+// ArrayArg implements driver.ArrayBinder.
+// The pgx binds the slices as the array parameters natively.
+func (d *DB) ArrayArg(slice any) any {
+	return slice
+}
 
-	if err == context.DeadlineExceeded {
-		return bserr.Timeout
+// ErrorCode implements driver.DB.
+func (d *DB) ErrorCode(err error) bserr.Code {
+	if code, ok := driver.SentinelErrorCode(err); ok {
+		return code
 	}
 
-	var pgerr *pgconn.PgError
-	if !errors.As(err, &pgerr) {
-		return bserr.Unknown
+	details, ok := d.ErrorDetails(err)
+	if !ok {
+		return connErrorCode(err)
 	}
 
-	switch pgerr.Code {
-	case "23505":
-		return bserr.UniqueViolation
-	case "23503":
-		return bserr.ForeignKeyViolation
-	case "23502":
-		return bserr.ConstraintViolation
-	case "23514":
-		return bserr.ConstraintViolation
-	case "42501":
-		return bserr.PermissionDenied
-	case "40001": // CockroachDB serialization failure.
-		return bserr.ConcurrentUpdate
-	case "CR000":
-		return bserr.ConcurrentUpdate
-	case "42P01":
-		return bserr.TableNotFound
-	case "53200":
-		return bserr.OutOfMemory
-	case "53100":
-		return bserr.OutOfDisk
-	case "53300":
-		return bserr.TooManyConnections
-	case "57000":
-		return bserr.Timeout
-	case "57P02":
-		return bserr.Timeout
-	case "XX000":
-		return bserr.InternalError
-	}
-	if strings.HasPrefix(pgerr.Code, "42") {
-		return bserr.InvalidInputSyntax
+	if code, ok := d.errorMappings.Code(details); ok {
+		return code
 	}
+	return bserr.Unknown
+}
 
-	if strings.HasPrefix(pgerr.Code, "22") {
-		return bserr.DataException
+// ErrorClass implements driver.ErrorClassifier.
+// The class of the error code is refined with the pgconn knowledge
+// whether the failed request has been sent to the server.
+func (d *DB) ErrorClass(err error) bserr.Class {
+	class := d.ErrorCode(err).Class()
+	if pgconn.SafeToRetry(err) {
+		class |= bserr.Retryable | bserr.Transient | bserr.SafeToRetry
 	}
-
-	if strings.HasPrefix(pgerr.Code, "23") {
-		return bserr.ConstraintViolation
+	if pgconn.Timeout(err) {
+		class |= bserr.Retryable | bserr.Transient
 	}
+	return class
+}
 
-	if strings.HasPrefix(pgerr.Code, "54") {
-		return bserr.InternalError // Program limit exceeded.
+// connErrorCode returns the code of the pgconn errors, that occur
+// while connecting to the server or waiting for its response.
+func connErrorCode(err error) bserr.Code {
+	if pgconn.Timeout(err) {
+		return bserr.Timeout
 	}
 
-	if strings.HasPrefix(pgerr.Code, "XX") {
-		return bserr.InternalError
+	// The pgconn connect error type is not exported,
+	// but all of its messages share the same prefix.
+	if strings.Contains(err.Error(), "failed to connect to") {
+		return bserr.ConnectionLost
 	}
-
 	return bserr.Unknown
 }
 
@@ -266,6 +285,29 @@ func (d *DB) ErrorConstraint(err error) string {
 	return pgerr.ConstraintName
 }
 
+// ErrorDetails implements driver.ErrorDetailer.
+func (d *DB) ErrorDetails(err error) (driver.ErrorDetails, bool) {
+	var pgerr *pgconn.PgError
+	if !errors.As(err, &pgerr) {
+		return driver.ErrorDetails{}, false
+	}
+	return driver.ErrorDetails{
+		SQLState:      pgerr.Code,
+		Severity:      pgerr.Severity,
+		Message:       pgerr.Message,
+		Detail:        pgerr.Detail,
+		Hint:          pgerr.Hint,
+		Position:      int(pgerr.Position),
+		InternalQuery: pgerr.InternalQuery,
+		Schema:        pgerr.SchemaName,
+		Table:         pgerr.TableName,
+		Column:        pgerr.ColumnName,
+		DataType:      pgerr.DataTypeName,
+		Constraint:    pgerr.ConstraintName,
+		Routine:       pgerr.Routine,
+	}, true
+}
+
 // DB returns the underlying database/sql.DB.
 func (d *DB) DB() *sql.DB {
 	return d.db
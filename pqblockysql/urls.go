@@ -20,7 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
-	"strings"
+	"strconv"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
 	"github.com/lib/pq"
@@ -28,6 +28,7 @@ import (
 	"github.com/blockysource/blockysql"
 	"github.com/blockysource/blockysql/bserr"
 	"github.com/blockysource/blockysql/driver"
+	"github.com/blockysource/blockysql/internal/pgshared"
 )
 
 func init() {
@@ -52,6 +53,16 @@ var _ blockysql.DBURLOpener = (*URLOpener)(nil)
 type URLOpener struct {
 	// TraceOpts contains options for OpenCensus.
 	TraceOpts []ocsql.TraceOption
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	// I.e. {SQLState: "P0001", Message: "access denied", Code: bserr.PermissionDenied}
+	// maps an application error raised by a function to bserr.PermissionDenied.
+	ErrorMappings driver.ErrorMappings
 }
 
 // OpenDBURL opens a new database connection for the given URL.
@@ -76,44 +87,65 @@ func (o *URLOpener) OpenDBURL(ctx context.Context, u *url.URL) (*blockysql.DB, e
 		return nil, fmt.Errorf("pqblockysql: open database failed: %v", err)
 	}
 
-	return OpenDB(ctx, cn, Options{TraceOpts: o.TraceOpts})
+	return OpenDB(ctx, cn, Options{
+		TraceOpts:     o.TraceOpts,
+		DBOptions:     o.DBOptions,
+		ErrorMappings: o.ErrorMappings,
+	})
 }
 
 // Options contains options for configuring the database connection.
 type Options struct {
 	// TraceOpts contains options for OpenCensus.
 	TraceOpts []ocsql.TraceOption
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	// I.e. {SQLState: "P0001", Message: "access denied", Code: bserr.PermissionDenied}
+	// maps an application error raised by a function to bserr.PermissionDenied.
+	ErrorMappings driver.ErrorMappings
 }
 
 // OpenDB returns a new bsql.DB backed by a *sql.DB.
 func OpenDB(ctx context.Context, c *pq.Connector, opts Options) (*blockysql.DB, error) {
 	db := sql.OpenDB(ocsql.WrapConnector(c, opts.TraceOpts...))
 
-	// Check if the database is CockroachDB.
-	row := db.QueryRowContext(ctx, "SELECT VERSION()")
-	var version string
-	if err := row.Scan(&version); err != nil {
-		return nil, fmt.Errorf("pqblockysql: open database failed: %v", err)
+	d := &DB{db: db, errorMappings: defaultErrorMappings.With(opts.ErrorMappings...)}
+	bdb, err := blockysql.NewDB(d, opts.DBOptions...)
+	if err != nil {
+		return nil, err
 	}
 
-	d := &DB{db: db}
-	if isCockroach := strings.Contains(version, "CockroachDB"); isCockroach {
-		d.dialect = driver.DialectCockroach
-	} else if isYugaByte := strings.Contains(version, "-YB-"); isYugaByte {
-		d.dialect = driver.DialectYugabyte
-	} else {
-		d.dialect = driver.DialectPostgres
+	// Check the dialect and flavor of the database.
+	// The errors of the probe are the first to reveal the connection
+	// and authentication failures, thus they are translated to carry their codes.
+	d.dialect, d.serverInfo, err = pgshared.ProbeServer(ctx, db)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("pqblockysql: open database failed: %w", bdb.TranslateError(err))
 	}
 
-	return blockysql.NewDB(d)
+	return bdb, nil
 }
 
-var _ driver.DB = (*DB)(nil)
+var (
+	_ driver.DB                  = (*DB)(nil)
+	_ driver.ErrorDetailer       = (*DB)(nil)
+	_ driver.ServerInformer      = (*DB)(nil)
+	_ driver.CapabilityDescriber = (*DB)(nil)
+	_ driver.ArrayBinder         = (*DB)(nil)
+)
 
 // DB is the driver for the PostgreSQL database.
 type DB struct {
-	db      *sql.DB
-	dialect string
+	db            *sql.DB
+	dialect       string
+	serverInfo    driver.ServerInfo
+	errorMappings driver.ErrorMappings
 }
 
 // DriverName implements driver.DB
@@ -126,79 +158,37 @@ func (d *DB) Dialect() string {
 	return d.dialect
 }
 
-// ErrorCode implements driver.DB.
-func (d *DB) ErrorCode(err error) bserr.Code {
-	if err == nil {
-		return bserr.OK
-	}
+// ServerInfo implements driver.ServerInformer.
+// The flavors of the postgres dialect are driver.FlavorAurora, driver.FlavorRedshift,
+// driver.FlavorAlloyDB and driver.FlavorNeon.
+func (d *DB) ServerInfo() driver.ServerInfo {
+	return d.serverInfo
+}
 
-	if err == sql.ErrNoRows {
-		return bserr.NotFound
-	}
+// Capabilities implements driver.CapabilityDescriber.
+func (d *DB) Capabilities() driver.Capabilities {
+	return driver.DefaultCapabilities(d.dialect, d.serverInfo)
+}
 
-	if err == sql.ErrTxDone {
-		return bserr.TxDone
-	}
+// ArrayArg implements driver.ArrayBinder.
+func (d *DB) ArrayArg(slice any) any {
+	return pq.Array(slice)
+}
 
-	if err == context.DeadlineExceeded {
-		return bserr.Timeout
+// ErrorCode implements driver.DB.
+func (d *DB) ErrorCode(err error) bserr.Code {
+	if code, ok := driver.SentinelErrorCode(err); ok {
+		return code
 	}
 
-	var pqerr *pq.Error
-	if !errors.As(err, &pqerr) {
+	details, ok := d.ErrorDetails(err)
+	if !ok {
 		return bserr.Unknown
 	}
 
-	switch pqerr.Code {
-	case "23505":
-		return bserr.UniqueViolation
-	case "23503":
-		return bserr.ForeignKeyViolation
-	case "23502":
-		return bserr.ConstraintViolation
-	case "23514":
-		return bserr.ConstraintViolation
-	case "42501":
-		return bserr.PermissionDenied
-	case "40001": // CockroachDB serialization failure.
-		return bserr.ConcurrentUpdate
-	case "CR000":
-		return bserr.ConcurrentUpdate
-	case "42P01":
-		return bserr.TableNotFound
-	case "53200":
-		return bserr.OutOfMemory
-	case "53100":
-		return bserr.OutOfDisk
-	case "53300":
-		return bserr.TooManyConnections
-	case "57000":
-		return bserr.Timeout
-	case "57P02":
-		return bserr.Timeout
-	case "XX000":
-		return bserr.InternalError
-	}
-	if strings.HasPrefix(string(pqerr.Code), "42") {
-		return bserr.InvalidInputSyntax
-	}
-
-	if strings.HasPrefix(string(pqerr.Code), "22") {
-		return bserr.DataException
-	}
-
-	if strings.HasPrefix(string(pqerr.Code), "23") {
-		return bserr.ConstraintViolation
-	}
-
-	if strings.HasPrefix(string(pqerr.Code), "54") {
-		return bserr.InternalError // Program limit exceeded.
+	if code, ok := d.errorMappings.Code(details); ok {
+		return code
 	}
-
-	if strings.HasPrefix(string(pqerr.Code), "XX") {
-		return bserr.InternalError
-	}
-
 	return bserr.Unknown
 }
 
@@ -250,6 +240,32 @@ func (d *DB) ErrorConstraint(err error) string {
 	return pqerr.Constraint
 }
 
+// ErrorDetails implements driver.ErrorDetailer.
+func (d *DB) ErrorDetails(err error) (driver.ErrorDetails, bool) {
+	var pqerr *pq.Error
+	if !errors.As(err, &pqerr) {
+		return driver.ErrorDetails{}, false
+	}
+
+	// The position is sent by the server as a string.
+	position, _ := strconv.Atoi(pqerr.Position)
+	return driver.ErrorDetails{
+		SQLState:      string(pqerr.Code),
+		Severity:      pqerr.Severity,
+		Message:       pqerr.Message,
+		Detail:        pqerr.Detail,
+		Hint:          pqerr.Hint,
+		Position:      position,
+		InternalQuery: pqerr.InternalQuery,
+		Schema:        pqerr.Schema,
+		Table:         pqerr.Table,
+		Column:        pqerr.Column,
+		DataType:      pqerr.DataTypeName,
+		Constraint:    pqerr.Constraint,
+		Routine:       pqerr.Routine,
+	}, true
+}
+
 // DB returns the underlying database/sql.DB.
 func (d *DB) DB() *sql.DB {
 	return d.db
@@ -0,0 +1,109 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// Querier is the common interface of the DB, Tx and Conn.
+// It allows to write the data access code once, regardless if it is
+// executed on the connection pool, within a transaction or on a single connection.
+type Querier interface {
+	// ExecContext executes a query without returning any rows.
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+
+	// QueryContext executes a query that returns rows, typically a SELECT.
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+
+	// QueryRowContext executes a query that is expected to return at most one row.
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+
+	// PrepareContext creates a prepared statement for later queries or executions.
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+
+	// NamedExecContext executes a query with the named parameters without returning any rows.
+	NamedExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+
+	// NamedQueryContext executes a query with the named parameters that returns rows, typically a SELECT.
+	NamedQueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+
+	// NamedQueryRowContext executes a query with the named parameters,
+	// that is expected to return at most one row.
+	NamedQueryRowContext(ctx context.Context, query string, args ...any) *Row
+
+	// PrepareNamedContext creates a prepared statement with the named parameters.
+	PrepareNamedContext(ctx context.Context, query string) (*NamedStmt, error)
+
+	// WrapRow returns the Row, that translates the errors of the given row.
+	WrapRow(row *sql.Row) *Row
+
+	// WrapRows returns the Rows, that translate the errors of the given rows.
+	WrapRows(rows *sql.Rows) *Rows
+
+	// WrapStmt returns the Stmt, that translates the errors of the given statement.
+	WrapStmt(stmt *sql.Stmt) *Stmt
+
+	// DriverName returns the name of the driver.
+	DriverName() string
+
+	// Dialect returns the dialect of the database connection.
+	Dialect() string
+
+	// Capabilities returns the features supported by the connected database server.
+	Capabilities() driver.Capabilities
+
+	// ErrorCode returns the error code of the given error
+	// if the driver supports it.
+	ErrorCode(err error) bserr.Code
+
+	// HasErrorDetails returns true if the driver supports error details,
+	// such as column, table and constraint name.
+	HasErrorDetails() bool
+
+	// ErrorColumn returns the column name of the given error
+	// if the driver doesn't support it, it returns an empty string.
+	ErrorColumn(err error) string
+
+	// ErrorTable returns the table name of the given error
+	// if the driver doesn't support it, it returns an empty string.
+	ErrorTable(err error) string
+
+	// ErrorConstraint returns the constraint name of the given error
+	// if the driver doesn't support it, it returns an empty string.
+	ErrorConstraint(err error) string
+
+	// ErrorClass returns the classification of the given error.
+	ErrorClass(err error) bserr.Class
+
+	// ErrorDetails returns the details of the given error.
+	// It returns false if the err is nil or the details are not available.
+	ErrorDetails(err error) (driver.ErrorDetails, bool)
+
+	// TranslateError translates the given driver error into the *bserr.Error,
+	// that carries its code and details, and wraps the original error.
+	TranslateError(err error) error
+}
+
+// Compile time check if the DB, Tx and Conn implement the Querier.
+var (
+	_ Querier = (*DB)(nil)
+	_ Querier = (*Tx)(nil)
+	_ Querier = (*Conn)(nil)
+)
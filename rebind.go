@@ -0,0 +1,106 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+// Rebind converts the question mark placeholders of the query
+// into the placeholder style of the given capabilities, i.e. for the driver.PlaceholderDollar:
+//
+//	SELECT * FROM users WHERE id = ? AND tenant = ?
+//
+// becomes:
+//
+//	SELECT * FROM users WHERE id = $1 AND tenant = $2
+//
+// The question marks within the string literals, quoted identifiers and comments
+// are left untouched. The doubled question mark "??" is replaced with a single
+// question mark, that is not a placeholder, i.e. to use the postgres jsonb ? operator.
+// The queries of the driver.PlaceholderQuestion style are returned unchanged,
+// including the "??", as a single question mark would be a placeholder there.
+func Rebind(caps driver.Capabilities, query string) string {
+	style := caps.Placeholder
+	if style == driver.PlaceholderQuestion || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		sb strings.Builder
+		n  int
+		l  = newLexer(caps)
+	)
+	sb.Grow(len(query) + 8)
+	for i := 0; i < len(query); {
+		if end, ok := l.skipNonCode(query, i); ok {
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		if c != '?' {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+
+		if i+1 < len(query) && query[i+1] == '?' {
+			sb.WriteByte('?')
+			i += 2
+			continue
+		}
+
+		n++
+		writePlaceholder(&sb, style, n)
+		i++
+	}
+	return sb.String()
+}
+
+// writePlaceholder writes the n-th placeholder of the given style, counting from 1.
+func writePlaceholder(sb *strings.Builder, style driver.PlaceholderStyle, n int) {
+	switch style {
+	case driver.PlaceholderDollar:
+		sb.WriteByte('$')
+	case driver.PlaceholderAtP:
+		sb.WriteString("@p")
+	case driver.PlaceholderColon:
+		sb.WriteByte(':')
+	default:
+		sb.WriteByte('?')
+		return
+	}
+	sb.WriteString(strconv.Itoa(n))
+}
+
+// Rebind converts the question mark placeholders of the query
+// into the placeholder style of the connected database.
+// See the Rebind function for details.
+func (d *DB) Rebind(query string) string {
+	return Rebind(d.Capabilities(), query)
+}
+
+// rebindQuery rebinds the query if the DB is configured with WithRebind.
+func (d *DB) rebindQuery(query string) string {
+	if !d.rebind {
+		return query
+	}
+	return d.Rebind(query)
+}
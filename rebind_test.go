@@ -0,0 +1,108 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"testing"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+func capsOf(dialect string) driver.Capabilities {
+	return driver.DefaultCapabilities(dialect, driver.ServerInfo{})
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		query   string
+		want    string
+	}{
+		{
+			name:    "dollar",
+			dialect: driver.DialectPostgres,
+			query:   "SELECT * FROM users WHERE id = ? AND tenant = ?",
+			want:    "SELECT * FROM users WHERE id = $1 AND tenant = $2",
+		},
+		{
+			name:    "at p",
+			dialect: driver.DialectMSSQL,
+			query:   "SELECT [a?] FROM t WHERE id = ?",
+			want:    "SELECT [a?] FROM t WHERE id = @p1",
+		},
+		{
+			name:    "colon",
+			dialect: driver.DialectOracle,
+			query:   "SELECT * FROM t WHERE a = ? AND b = ?",
+			want:    "SELECT * FROM t WHERE a = :1 AND b = :2",
+		},
+		{
+			name:    "question unchanged",
+			dialect: driver.DialectMySQL,
+			query:   "SELECT * FROM t WHERE id = ?",
+			want:    "SELECT * FROM t WHERE id = ?",
+		},
+		{
+			name:    "doubled question mark",
+			dialect: driver.DialectPostgres,
+			query:   "SELECT * FROM t WHERE data ?? 'key' AND id = ?",
+			want:    "SELECT * FROM t WHERE data ? 'key' AND id = $1",
+		},
+		{
+			name:    "literals and comments",
+			dialect: driver.DialectPostgres,
+			query:   "SELECT '?', \"?\", $$?$$, $tag$?$tag$ -- ?\n/* ? /* ? */ ? */ FROM t WHERE id = ?",
+			want:    "SELECT '?', \"?\", $$?$$, $tag$?$tag$ -- ?\n/* ? /* ? */ ? */ FROM t WHERE id = $1",
+		},
+		{
+			name:    "postgres escape string",
+			dialect: driver.DialectPostgres,
+			query:   "SELECT E'it\\'s ?', 'a\\' WHERE id = ?",
+			want:    "SELECT E'it\\'s ?', 'a\\' WHERE id = $1",
+		},
+		{
+			name:    "postgres standard string",
+			dialect: driver.DialectPostgres,
+			query:   "SELECT 'a\\', ? WHERE id = ?",
+			want:    "SELECT 'a\\', $1 WHERE id = $2",
+		},
+		{
+			name:    "mysql doubled question mark",
+			dialect: driver.DialectMySQL,
+			query:   "SELECT 'it\\'s ??' FROM t WHERE a = ?? AND b = ?",
+			want:    "SELECT 'it\\'s ??' FROM t WHERE a = ?? AND b = ?",
+		},
+		{
+			name:    "sqlite doubled question mark",
+			dialect: driver.DialectSQLite,
+			query:   "SELECT 1 -- ??\nFROM t WHERE a = ?? AND b = ?",
+			want:    "SELECT 1 -- ??\nFROM t WHERE a = ?? AND b = ?",
+		},
+		{
+			name:    "postgres hash operator",
+			dialect: driver.DialectPostgres,
+			query:   "SELECT 1 # 2 WHERE id = ?",
+			want:    "SELECT 1 # 2 WHERE id = $1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rebind(capsOf(tt.dialect), tt.query); got != tt.want {
+				t.Errorf("Rebind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
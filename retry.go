@@ -0,0 +1,245 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"time"
+
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// RetryPolicy defines how a transaction is retried when it fails
+// with a retryable error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// If it is not positive, the DefaultRetryPolicy.MaxAttempts is used.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper bound of the delay between the attempts.
+	// If it is zero, the delay is not limited.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the delay grows after each attempt.
+	// If it is less than 1, the delay is constant.
+	Multiplier float64
+
+	// Jitter is the fraction of the delay that is randomized, in range [0, 1].
+	// I.e. a jitter of 0.2 results in a delay between 80% and 120% of the backoff.
+	Jitter float64
+
+	// RetryableCodes are the error codes for which the transaction is retried.
+	// A code matches also the errors with the codes that refine it,
+	// i.e. the bserr.ConcurrentUpdate matches the bserr.Deadlock.
+	// If empty, the errors classified as both bserr.Retryable
+	// and bserr.SafeToRetry are retried.
+	RetryableCodes []bserr.Code
+
+	// OnRetry is an optional hook called before each retry,
+	// with the number of the failed attempt and its error.
+	OnRetry func(attempt int, err error)
+}
+
+// DefaultRetryPolicy is the retry policy used when no policy is provided.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// maxAttempts returns the maximum number of attempts of the policy.
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+	return DefaultRetryPolicy.MaxAttempts
+}
+
+// isRetryable checks if the given error should be retried.
+func (p *RetryPolicy) isRetryable(d *DB, err error) bool {
+	if len(p.RetryableCodes) == 0 {
+		return d.ErrorClass(err).Has(bserr.Retryable | bserr.SafeToRetry)
+	}
+	code := d.ErrorCode(err)
+	for _, c := range p.RetryableCodes {
+		if c == code || c == code.Parent() {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the retry that follows the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	if p.Multiplier > 1 {
+		for i := 1; i < attempt; i++ {
+			delay *= p.Multiplier
+			if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+				break
+			}
+		}
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// wait waits before the retry that follows the given attempt.
+// It returns false if the context is done before the delay elapses.
+func (p *RetryPolicy) wait(ctx context.Context, attempt int) bool {
+	t := time.NewTimer(p.backoff(attempt))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// RunInTransactionRetry runs the given function in a transaction and retries
+// the whole transaction if it fails with an error that is retryable
+// according to the policy. The failure may occur at any stage
+// of the transaction: begin, the function execution or the commit.
+// If the policy is nil the DefaultRetryPolicy is used.
+// The function must be safe to be called multiple times.
+// It returns the number of attempts made and the error of the last attempt.
+//
+// On CockroachDB the transaction is retried within the same session
+// using the cockroach_restart savepoint, as recommended by the database.
+// If the context already carries a transaction started by this DB,
+// the function is run once within a savepoint, leaving the retries
+// to the outermost transaction.
+func (d *DB) RunInTransactionRetry(ctx context.Context, opts *sql.TxOptions, policy *RetryPolicy, fn func(ctx context.Context, tx *Tx) error) (int, error) {
+	if policy == nil {
+		policy = &DefaultRetryPolicy
+	}
+
+	if _, ok := d.txState(ctx); ok {
+		return 1, d.RunInTransaction(ctx, opts, fn)
+	}
+
+	if d.Dialect() == driver.DialectCockroach {
+		return d.runCockroachRetry(ctx, opts, policy, fn)
+	}
+
+	maxAttempts := policy.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		err := d.RunInTransaction(ctx, opts, fn)
+		if err == nil {
+			return attempt, nil
+		}
+
+		if attempt >= maxAttempts || !policy.isRetryable(d, err) {
+			return attempt, err
+		}
+
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err)
+		}
+
+		if !policy.wait(ctx, attempt) {
+			return attempt, err
+		}
+	}
+}
+
+// runCockroachRetry implements the CockroachDB client-side retry protocol.
+// The function is run after the cockroach_restart savepoint, and on
+// a retryable error the transaction is rolled back to that savepoint
+// and the function is run again. If the transaction could not be begun,
+// committed or rolled back to the savepoint, the retry starts a new transaction.
+func (d *DB) runCockroachRetry(ctx context.Context, opts *sql.TxOptions, policy *RetryPolicy, fn func(ctx context.Context, tx *Tx) error) (int, error) {
+	var tx *Tx
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	maxAttempts := policy.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		var err error
+		if tx == nil {
+			tx, err = d.beginCockroachRestart(ctx, opts)
+		}
+		if err == nil {
+			err = fn(d.withTxState(ctx, &txState{tx: tx}), tx)
+		}
+		if err == nil {
+			// The serialization failures are reported on release.
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+cockroachRestartSavepoint)
+		}
+		if err == nil {
+			// The transaction is finished, whether the commit succeeds or not.
+			err = tx.Commit()
+			tx = nil
+			if err == nil {
+				return attempt, nil
+			}
+		}
+
+		if attempt >= maxAttempts || !policy.isRetryable(d, err) {
+			return attempt, err
+		}
+
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err)
+		}
+
+		if !policy.wait(ctx, attempt) {
+			return attempt, err
+		}
+
+		if tx != nil {
+			if _, rerr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+cockroachRestartSavepoint); rerr != nil {
+				_ = tx.Rollback()
+				tx = nil
+			}
+		}
+	}
+}
+
+// beginCockroachRestart begins a transaction and creates
+// the cockroach_restart savepoint within it.
+func (d *DB) beginCockroachRestart(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := d.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+cockroachRestartSavepoint); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
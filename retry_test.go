@@ -0,0 +1,312 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// retryConnector is a connector of the stubConn, that counts the begun
+// transactions and fails their commits with the errStubConflict
+// the given number of times.
+type retryConnector struct {
+	begins      int
+	commitFails int
+}
+
+func (c *retryConnector) Connect(context.Context) (sqldriver.Conn, error) {
+	return retryConn{c: c}, nil
+}
+
+func (c *retryConnector) Driver() sqldriver.Driver { return nil }
+
+// retryConn is a stubConn, whose transactions are controlled by the retryConnector.
+type retryConn struct {
+	stubConn
+	c *retryConnector
+}
+
+func (c retryConn) Begin() (sqldriver.Tx, error) {
+	c.c.begins++
+	return retryTx{c: c.c}, nil
+}
+
+// retryTx is a transaction, whose commit fails while the retryConnector has commit failures left.
+type retryTx struct {
+	c *retryConnector
+}
+
+func (tx retryTx) Commit() error {
+	if tx.c.commitFails > 0 {
+		tx.c.commitFails--
+		return errStubConflict
+	}
+	return nil
+}
+
+func (retryTx) Rollback() error { return nil }
+
+func newRetryDB(t *testing.T, dialect string, commitFails int) (*DB, *retryConnector) {
+	t.Helper()
+	c := &retryConnector{commitFails: commitFails}
+	sqlDB := sql.OpenDB(c)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := NewDB(&stubDriver{db: sqlDB, dialect: dialect})
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	return db, c
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		min, max time.Duration
+	}{
+		{
+			name:    "initial",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2},
+			attempt: 1,
+			min:     100 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "exponential",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2},
+			attempt: 3,
+			min:     400 * time.Millisecond,
+			max:     400 * time.Millisecond,
+		},
+		{
+			name:    "max backoff",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2},
+			attempt: 10,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name:    "constant",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 0.5},
+			attempt: 4,
+			min:     100 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "jitter",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2, Jitter: 0.2},
+			attempt: 2,
+			min:     160 * time.Millisecond,
+			max:     240 * time.Millisecond,
+		},
+		{
+			name:    "jitter over max backoff",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2, Jitter: 0.2},
+			attempt: 10,
+			min:     800 * time.Millisecond,
+			max:     1200 * time.Millisecond,
+		},
+		{
+			name:    "jitter clamped",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: 3},
+			attempt: 1,
+			min:     0,
+			max:     200 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := tt.policy.backoff(tt.attempt); got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%d) = %v, want in range [%v, %v]", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRunInTransactionRetry(t *testing.T) {
+	errFatal := errors.New("fatal")
+	deadlock := &bserr.Error{Code: bserr.Deadlock}
+
+	tests := []struct {
+		name         string
+		policy       RetryPolicy
+		errs         []error
+		wantAttempts int
+		wantErr      error
+		wantRetries  []int
+	}{
+		{
+			name:         "success",
+			wantAttempts: 1,
+		},
+		{
+			name:         "retried until success",
+			errs:         []error{errStubConflict, errStubConflict},
+			wantAttempts: 3,
+			wantRetries:  []int{1, 2},
+		},
+		{
+			name:         "max attempts",
+			policy:       RetryPolicy{MaxAttempts: 2},
+			errs:         []error{errStubConflict, errStubConflict, errStubConflict},
+			wantAttempts: 2,
+			wantErr:      errStubConflict,
+			wantRetries:  []int{1},
+		},
+		{
+			name:         "not retryable",
+			errs:         []error{errFatal},
+			wantAttempts: 1,
+			wantErr:      errFatal,
+		},
+		{
+			name:         "retryable code parent",
+			policy:       RetryPolicy{RetryableCodes: []bserr.Code{bserr.ConcurrentUpdate}},
+			errs:         []error{deadlock},
+			wantAttempts: 2,
+			wantRetries:  []int{1},
+		},
+		{
+			name:         "code not in retryable codes",
+			policy:       RetryPolicy{RetryableCodes: []bserr.Code{bserr.UniqueViolation}},
+			errs:         []error{errStubConflict},
+			wantAttempts: 1,
+			wantErr:      errStubConflict,
+		},
+	}
+	for _, dialect := range []string{driver.DialectPostgres, driver.DialectCockroach} {
+		for _, tt := range tests {
+			t.Run(dialect+"/"+tt.name, func(t *testing.T) {
+				db, c := newRetryDB(t, dialect, 0)
+
+				var retries []int
+				policy := tt.policy
+				policy.OnRetry = func(attempt int, err error) {
+					retries = append(retries, attempt)
+				}
+
+				calls := 0
+				attempts, err := db.RunInTransactionRetry(context.Background(), nil, &policy, func(ctx context.Context, tx *Tx) error {
+					calls++
+					if calls <= len(tt.errs) {
+						return tt.errs[calls-1]
+					}
+					return nil
+				})
+				if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+					t.Errorf("RunInTransactionRetry() error = %v, want %v", err, tt.wantErr)
+				}
+				if attempts != tt.wantAttempts || calls != tt.wantAttempts {
+					t.Errorf("RunInTransactionRetry() attempts = %d, calls = %d, want %d", attempts, calls, tt.wantAttempts)
+				}
+				// The stub fails the ROLLBACK TO SAVEPOINT, thus each retry
+				// on CockroachDB also starts a new transaction.
+				if c.begins != tt.wantAttempts {
+					t.Errorf("begun transactions = %d, want %d", c.begins, tt.wantAttempts)
+				}
+				if !reflect.DeepEqual(retries, tt.wantRetries) {
+					t.Errorf("OnRetry attempts = %v, want %v", retries, tt.wantRetries)
+				}
+			})
+		}
+	}
+}
+
+func TestRunInTransactionRetryCommitFailure(t *testing.T) {
+	for _, dialect := range []string{driver.DialectPostgres, driver.DialectCockroach} {
+		t.Run(dialect, func(t *testing.T) {
+			db, c := newRetryDB(t, dialect, 2)
+
+			attempts, err := db.RunInTransactionRetry(context.Background(), nil, &RetryPolicy{}, func(ctx context.Context, tx *Tx) error {
+				return nil
+			})
+			if err != nil {
+				t.Errorf("RunInTransactionRetry() error = %v", err)
+			}
+			if attempts != 3 || c.begins != 3 {
+				t.Errorf("RunInTransactionRetry() attempts = %d, begun transactions = %d, want 3", attempts, c.begins)
+			}
+		})
+	}
+}
+
+func TestRunInTransactionRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db, _ := newRetryDB(t, driver.DialectPostgres, 0)
+	policy := RetryPolicy{
+		InitialBackoff: time.Hour,
+		OnRetry:        func(int, error) { cancel() },
+	}
+
+	done := make(chan struct{})
+	var (
+		attempts int
+		err      error
+	)
+	go func() {
+		defer close(done)
+		attempts, err = db.RunInTransactionRetry(ctx, nil, &policy, func(ctx context.Context, tx *Tx) error {
+			return errStubConflict
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunInTransactionRetry() didn't return after the context was canceled")
+	}
+	if attempts != 1 || !errors.Is(err, errStubConflict) {
+		t.Errorf("RunInTransactionRetry() = (%d, %v), want (1, %v)", attempts, err, errStubConflict)
+	}
+}
+
+func TestRunInTransactionRetryNested(t *testing.T) {
+	ctx := context.Background()
+	db, c := newRetryDB(t, driver.DialectPostgres, 0)
+
+	var (
+		calls    int
+		attempts int
+	)
+	_ = db.RunInTransaction(ctx, nil, func(ctx context.Context, tx *Tx) error {
+		var err error
+		attempts, err = db.RunInTransactionRetry(ctx, nil, nil, func(ctx context.Context, tx *Tx) error {
+			calls++
+			return errStubConflict
+		})
+		if !errors.Is(err, errStubConflict) {
+			t.Errorf("RunInTransactionRetry() error = %v, want %v", err, errStubConflict)
+		}
+		return nil
+	})
+	if attempts != 1 || calls != 1 || c.begins != 1 {
+		t.Errorf("RunInTransactionRetry() attempts = %d, calls = %d, begun transactions = %d, want 1", attempts, calls, c.begins)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"database/sql"
+)
+
+// Row is the result of selecting a single row, returned by the WrapRow
+// and the NamedQueryRowContext. It is a wrapper over the database/sql.Row,
+// that translates the errors if the DB is configured to do so.
+type Row struct {
+	db  *DB
+	row *sql.Row
+
+	// err is the error that occurred before the query was executed,
+	// i.e. while binding the named args.
+	err error
+}
+
+// Scan copies the columns from the matched row into the values
+// pointed at by dest. If more than one row matches the query,
+// Scan uses the first row and discards the rest. If no row matches
+// the query, Scan returns an error matching the sql.ErrNoRows.
+func (r *Row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.db.wrapErr(r.row.Scan(dest...))
+}
+
+// Err provides a way for wrapping packages to check for
+// query errors without calling Scan.
+func (r *Row) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.db.wrapErr(r.row.Err())
+}
+
+// Row returns the underlying database/sql.Row.
+// It is nil if the query failed before its execution.
+func (r *Row) Row() *sql.Row {
+	return r.row
+}
+
+// WrapRow returns the Row, that translates the errors of the given row,
+// if the DB is configured with the WithErrorTranslation.
+func (d *DB) WrapRow(row *sql.Row) *Row {
+	return &Row{db: d, row: row}
+}
+
+// WrapRow returns the Row, that translates the errors of the given row,
+// if the DB is configured with the WithErrorTranslation.
+func (t *Tx) WrapRow(row *sql.Row) *Row {
+	return t.db.WrapRow(row)
+}
+
+// WrapRow returns the Row, that translates the errors of the given row,
+// if the DB is configured with the WithErrorTranslation.
+func (c *Conn) WrapRow(row *sql.Row) *Row {
+	return c.db.WrapRow(row)
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"database/sql"
+)
+
+// Rows is the result of a query. Its cursor starts before the first row
+// of the result set. It is a wrapper over the database/sql.Rows,
+// that translates the errors if the DB is configured to do so.
+type Rows struct {
+	db   *DB
+	rows *sql.Rows
+}
+
+// Next prepares the next result row for reading with the Scan method.
+// It returns false when there is no next row or an error happened
+// while preparing it. The Err should be consulted to distinguish between the two cases.
+func (r *Rows) Next() bool {
+	return r.rows.Next()
+}
+
+// NextResultSet prepares the next result set for reading.
+// It reports whether there is further result sets, or false if there
+// is no further result set or if there is an error advancing to it.
+// The Err should be consulted to distinguish between the two cases.
+func (r *Rows) NextResultSet() bool {
+	return r.rows.NextResultSet()
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (r *Rows) Err() error {
+	return r.db.wrapErr(r.rows.Err())
+}
+
+// Columns returns the column names.
+func (r *Rows) Columns() ([]string, error) {
+	cols, err := r.rows.Columns()
+	return cols, r.db.wrapErr(err)
+}
+
+// ColumnTypes returns column information such as column type, length, and nullable.
+func (r *Rows) ColumnTypes() ([]*sql.ColumnType, error) {
+	types, err := r.rows.ColumnTypes()
+	return types, r.db.wrapErr(err)
+}
+
+// Scan copies the columns in the current row into the values pointed at by dest.
+func (r *Rows) Scan(dest ...any) error {
+	return r.db.wrapErr(r.rows.Scan(dest...))
+}
+
+// Close closes the Rows, preventing further enumeration.
+func (r *Rows) Close() error {
+	return r.db.wrapErr(r.rows.Close())
+}
+
+// Rows returns the underlying database/sql.Rows.
+func (r *Rows) Rows() *sql.Rows {
+	return r.rows
+}
+
+// WrapRows returns the Rows, that translate the errors of the given rows,
+// if the DB is configured with the WithErrorTranslation.
+// It returns nil if the rows are nil, i.e. when the query failed.
+func (d *DB) WrapRows(rows *sql.Rows) *Rows {
+	if rows == nil {
+		return nil
+	}
+	return &Rows{db: d, rows: rows}
+}
+
+// WrapRows returns the Rows, that translate the errors of the given rows,
+// if the DB is configured with the WithErrorTranslation.
+// It returns nil if the rows are nil, i.e. when the query failed.
+func (t *Tx) WrapRows(rows *sql.Rows) *Rows {
+	return t.db.WrapRows(rows)
+}
+
+// WrapRows returns the Rows, that translate the errors of the given rows,
+// if the DB is configured with the WithErrorTranslation.
+// It returns nil if the rows are nil, i.e. when the query failed.
+func (c *Conn) WrapRows(rows *sql.Rows) *Rows {
+	return c.db.WrapRows(rows)
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blockysource/blockysql/driver"
+)
+
+// ErrSavepointsNotSupported is returned by the nested RunInTransaction calls
+// if the database doesn't support the savepoints.
+var ErrSavepointsNotSupported = errors.New("blockysql: savepoints are not supported by the database")
+
+// cockroachRestartSavepoint is the name of the savepoint used by the
+// CockroachDB client-side transaction retry protocol.
+// See https://www.cockroachlabs.com/docs/stable/advanced-client-side-transaction-retries.
+const cockroachRestartSavepoint = "cockroach_restart"
+
+// savepointSyntax defines the statements used to manage savepoints in a dialect.
+// Each statement is a format string that takes the savepoint name.
+type savepointSyntax struct {
+	// create creates a new savepoint.
+	create string
+	// release releases the savepoint, empty if the dialect doesn't support it.
+	release string
+	// rollback rolls back the transaction to the savepoint.
+	rollback string
+}
+
+// defaultSavepointSyntax is the SQL standard savepoint syntax,
+// used by postgres, mysql, sqlite, cockroach, yugabyte and tidb.
+var defaultSavepointSyntax = savepointSyntax{
+	create:   "SAVEPOINT %s",
+	release:  "RELEASE SAVEPOINT %s",
+	rollback: "ROLLBACK TO SAVEPOINT %s",
+}
+
+// savepointSyntaxes contains the savepoint syntax of the dialects
+// that differ from the defaultSavepointSyntax.
+var savepointSyntaxes = map[string]savepointSyntax{
+	driver.DialectMSSQL: {
+		create:   "SAVE TRANSACTION %s",
+		rollback: "ROLLBACK TRANSACTION %s",
+	},
+	driver.DialectOracle: {
+		create:   "SAVEPOINT %s",
+		rollback: "ROLLBACK TO SAVEPOINT %s",
+	},
+}
+
+// savepointSyntaxFor returns the savepoint syntax of the given dialect.
+func savepointSyntaxFor(dialect string) savepointSyntax {
+	if s, ok := savepointSyntaxes[dialect]; ok {
+		return s
+	}
+	return defaultSavepointSyntax
+}
+
+// runInSavepoint runs the given function within a savepoint of the
+// transaction stored in the context. If the function fails the transaction
+// is rolled back to the savepoint, otherwise the savepoint is released.
+func (d *DB) runInSavepoint(ctx context.Context, ts *txState, fn func(ctx context.Context, tx *Tx) error) error {
+	if !d.Capabilities().Savepoints {
+		return fmt.Errorf("%w: nested transaction on the %s dialect", ErrSavepointsNotSupported, d.Dialect())
+	}
+	syntax := savepointSyntaxFor(d.Dialect())
+
+	ts.savepoints++
+	name := fmt.Sprintf("blockysql_sp_%d", ts.savepoints)
+
+	if _, err := ts.tx.ExecContext(ctx, fmt.Sprintf(syntax.create, name)); err != nil {
+		return err
+	}
+
+	if err := fn(ctx, ts.tx); err != nil {
+		if _, rbErr := ts.tx.ExecContext(ctx, fmt.Sprintf(syntax.rollback, name)); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("blockysql: rollback to savepoint %s failed: %w", name, rbErr))
+		}
+		return err
+	}
+
+	if syntax.release == "" {
+		return nil
+	}
+	_, err := ts.tx.ExecContext(ctx, fmt.Sprintf(syntax.release, name))
+	return err
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqliteblockysql
+
+import (
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// defaultErrorMappings maps the SQLite result codes to the bserr codes.
+// The extended result codes precede the primary ones, that match
+// also the extended codes not listed in the table.
+var defaultErrorMappings = driver.ErrorMappings{
+	{Number: 2067, Code: bserr.UniqueViolation},    // SQLITE_CONSTRAINT_UNIQUE
+	{Number: 1555, Code: bserr.UniqueViolation},    // SQLITE_CONSTRAINT_PRIMARYKEY
+	{Number: 2579, Code: bserr.UniqueViolation},    // SQLITE_CONSTRAINT_ROWID
+	{Number: 787, Code: bserr.ForeignKeyViolation}, // SQLITE_CONSTRAINT_FOREIGNKEY
+	{Number: 1299, Code: bserr.NotNullViolation},   // SQLITE_CONSTRAINT_NOTNULL
+	{Number: 275, Code: bserr.CheckViolation},      // SQLITE_CONSTRAINT_CHECK
+	{Number: 3091, Code: bserr.DataException},      // SQLITE_CONSTRAINT_DATATYPE
+	{Number: 517, Code: bserr.ConcurrentUpdate},    // SQLITE_BUSY_SNAPSHOT
+	{Number: 1, Message: "no such table", Code: bserr.TableNotFound},
+	{Number: 1, Message: "syntax error", Code: bserr.InvalidInputSyntax},
+	{Number: 1, Message: "no such column", Code: bserr.InvalidInputSyntax},
+	{Number: 19, Code: bserr.ConstraintViolation}, // SQLITE_CONSTRAINT
+	{Number: 5, Code: bserr.LockNotAvailable},     // SQLITE_BUSY
+	{Number: 6, Code: bserr.LockNotAvailable},     // SQLITE_LOCKED
+	{Number: 9, Code: bserr.Canceled},             // SQLITE_INTERRUPT
+	{Number: 7, Code: bserr.OutOfMemory},          // SQLITE_NOMEM
+	{Number: 13, Code: bserr.OutOfDisk},           // SQLITE_FULL
+	{Number: 8, Code: bserr.ReadOnlyTransaction},  // SQLITE_READONLY
+	{Number: 3, Code: bserr.PermissionDenied},     // SQLITE_PERM
+	{Number: 23, Code: bserr.PermissionDenied},    // SQLITE_AUTH
+	{Number: 18, Code: bserr.StringTooLong},       // SQLITE_TOOBIG
+	{Number: 20, Code: bserr.DataException},       // SQLITE_MISMATCH
+	{Number: 25, Code: bserr.DataException},       // SQLITE_RANGE
+	{Number: 2, Code: bserr.InternalError},        // SQLITE_INTERNAL
+	{Number: 10, Code: bserr.InternalError},       // SQLITE_IOERR
+	{Number: 11, Code: bserr.InternalError},       // SQLITE_CORRUPT
+	{Number: 14, Code: bserr.InternalError},       // SQLITE_CANTOPEN
+}
+
+// DefaultErrorMappings returns a copy of the default table
+// that maps the SQLite result codes to the bserr codes.
+func DefaultErrorMappings() driver.ErrorMappings {
+	return append(driver.ErrorMappings(nil), defaultErrorMappings...)
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqliteblockysql
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// constraintColumnsRegexp matches the columns of the unique and not null constraint errors, i.e.:
+	//  - UNIQUE constraint failed: users.email
+	//  - UNIQUE constraint failed: users.first_name, users.last_name
+	//  - NOT NULL constraint failed: users.name
+	constraintColumnsRegexp = regexp.MustCompile(`(?:UNIQUE|NOT NULL) constraint failed: ([^ ,(]+(?:, [^ ,(]+)*)`)
+
+	// checkConstraintRegexp matches the name or expression of the check constraint error, i.e.:
+	//  CHECK constraint failed: price_positive
+	checkConstraintRegexp = regexp.MustCompile(`CHECK constraint failed: (.+?)(?: \(\d+\))?$`)
+
+	// tableRegexp matches the table of the table not found error, i.e.:
+	//  no such table: main.users
+	tableRegexp = regexp.MustCompile(`no such table: ([^ ]+)`)
+)
+
+// messageDetails contains the names extracted from the error message.
+type messageDetails struct {
+	table      string
+	column     string
+	constraint string
+}
+
+// parseErrorMessage extracts the table, column and constraint names
+// from the message of the error with given primary result code.
+// The extraction is best-effort, as SQLite doesn't report
+// the names of the unique, not null and foreign key constraints.
+func parseErrorMessage(code int, msg string) messageDetails {
+	var md messageDetails
+	switch code {
+	case 19: // SQLITE_CONSTRAINT
+		if m := constraintColumnsRegexp.FindStringSubmatch(msg); m != nil {
+			columns := strings.Split(m[1], ", ")
+			if table, column, ok := strings.Cut(columns[0], "."); ok {
+				md.table, md.column = table, column
+			} else {
+				md.column = columns[0]
+			}
+			// The column is set only for single column constraints.
+			if len(columns) > 1 {
+				md.column = ""
+			}
+			break
+		}
+		if m := checkConstraintRegexp.FindStringSubmatch(msg); m != nil {
+			md.constraint = m[1]
+		}
+	case 1: // SQLITE_ERROR
+		m := tableRegexp.FindStringSubmatch(msg)
+		if m == nil {
+			break
+		}
+		// The table name may be prefixed with the schema name.
+		if _, table, ok := strings.Cut(m[1], "."); ok {
+			md.table = table
+		} else {
+			md.table = m[1]
+		}
+	}
+	return md
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqliteblockysql
+
+import "testing"
+
+func TestParseErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		msg  string
+		want messageDetails
+	}{
+		{
+			name: "unique",
+			code: 19,
+			msg:  "UNIQUE constraint failed: users.email (2067)",
+			want: messageDetails{table: "users", column: "email"},
+		},
+		{
+			name: "unique multi column",
+			code: 19,
+			msg:  "UNIQUE constraint failed: users.first_name, users.last_name (2067)",
+			want: messageDetails{table: "users"},
+		},
+		{
+			name: "not null without table",
+			code: 19,
+			msg:  "NOT NULL constraint failed: name",
+			want: messageDetails{column: "name"},
+		},
+		{
+			name: "check",
+			code: 19,
+			msg:  "CHECK constraint failed: price_positive (275)",
+			want: messageDetails{constraint: "price_positive"},
+		},
+		{
+			name: "no such table",
+			code: 1,
+			msg:  "no such table: main.users",
+			want: messageDetails{table: "users"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseErrorMessage(tt.code, tt.msg); got != tt.want {
+				t.Errorf("parseErrorMessage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
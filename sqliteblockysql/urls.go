@@ -0,0 +1,342 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqliteblockysql provides the blockysql driver for the SQLite database,
+// based on the pure-Go modernc.org/sqlite implementation.
+package sqliteblockysql
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"contrib.go.opencensus.io/integrations/ocsql"
+	"modernc.org/sqlite"
+
+	"github.com/blockysource/blockysql"
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+func init() {
+	// Register the driver.
+	blockysql.DefaultURLMux().RegisterDB(SchemaName, new(URLOpener))
+}
+
+// SchemaName is the name of the driver.
+const SchemaName = "sqlite"
+
+// memoryPath is the path of the in-memory database.
+const memoryPath = ":memory:"
+
+// uriParams are the SQLite URI parameters, that are passed as is to the database.
+// See https://www.sqlite.org/uri.html#recognized_query_parameters.
+var uriParams = map[string]bool{
+	"vfs":       true,
+	"mode":      true,
+	"cache":     true,
+	"psow":      true,
+	"nolock":    true,
+	"immutable": true,
+}
+
+var (
+	_ blockysql.DBURLOpener = (*URLOpener)(nil)
+	_ blockysql.DBURLParser = (*URLOpener)(nil)
+)
+
+// URLOpener is a sqlite driver url opener.
+// It opens the URLs of the form:
+//
+//	sqlite:///absolute/path/to/file.db?journal_mode=WAL&busy_timeout=5000
+//	sqlite://relative/path/to/file.db
+//	sqlite::memory:
+//	sqlite://:memory:
+//
+// The query parameters are executed as PRAGMA statements on each new connection,
+// except of the SQLite URI parameters (i.e. mode, cache) and the parameters
+// prefixed with an underscore (i.e. _txlock), that are passed as is to the driver.
+// The foreign keys are enforced unless the foreign_keys parameter is set.
+type URLOpener struct {
+	// TraceOpts contains options for OpenCensus.
+	TraceOpts []ocsql.TraceOption
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	// I.e. {Number: 1811, Message: "access denied", Code: bserr.PermissionDenied}
+	// maps an application error raised by the RAISE function in a trigger to bserr.PermissionDenied.
+	ErrorMappings driver.ErrorMappings
+}
+
+// OpenDBURL opens a new database connection for the given URL.
+func (o *URLOpener) OpenDBURL(ctx context.Context, u *url.URL) (*blockysql.DB, error) {
+	dsn, err := dsnFromURL(u)
+	if err != nil {
+		return nil, fmt.Errorf("sqliteblockysql: open database failed: %v", err)
+	}
+
+	return OpenDB(ctx, dsn, Options{
+		TraceOpts:     o.TraceOpts,
+		DBOptions:     o.DBOptions,
+		ErrorMappings: o.ErrorMappings,
+	})
+}
+
+// ParseDBURL implements blockysql.DBURLParser.
+// It parses the sqlite://:memory: URL, which is not valid for the url.Parse,
+// as the sqlite::memory:.
+func (o *URLOpener) ParseDBURL(urlstr string) (*url.URL, error) {
+	if scheme, rest, ok := strings.Cut(urlstr, "://"); ok {
+		if rest == memoryPath || strings.HasPrefix(rest, memoryPath+"?") {
+			urlstr = scheme + ":" + rest
+		}
+	}
+	return url.Parse(urlstr)
+}
+
+// dsnFromURL translates the sqlite URL into the modernc.org/sqlite data source name.
+func dsnFromURL(u *url.URL) (string, error) {
+	var path string
+	switch {
+	case u.Opaque != "":
+		// sqlite::memory: or sqlite:file.db
+		path = u.Opaque
+	case u.Host != "":
+		// sqlite://relative/path/to/file.db
+		path = u.Host + u.Path
+	default:
+		// sqlite:///absolute/path/to/file.db
+		path = u.Path
+	}
+	if path == "" {
+		return "", errors.New("database path is empty")
+	}
+	// A path starting with a double slash would be read as the URI authority.
+	if strings.HasPrefix(path, "//") {
+		path = "/" + strings.TrimLeft(path, "/")
+	}
+
+	query := url.Values{}
+	foreignKeys := false
+	for key, values := range u.Query() {
+		switch {
+		case uriParams[key], strings.HasPrefix(key, "_"):
+			query[key] = values
+		default:
+			if key == "foreign_keys" {
+				foreignKeys = true
+			}
+			for _, v := range values {
+				query.Add("_pragma", fmt.Sprintf("%s(%s)", key, v))
+			}
+		}
+	}
+	if !foreignKeys {
+		query.Add("_pragma", "foreign_keys(1)")
+	}
+
+	dsn := "file:" + path
+	if len(query) > 0 {
+		dsn += "?" + query.Encode()
+	}
+	return dsn, nil
+}
+
+// Options contains options for opening the database.
+type Options struct {
+	// TraceOpts contains options for OpenCensus.
+	TraceOpts []ocsql.TraceOption
+
+	// DBOptions contains options for configuring the blockysql.DB,
+	// i.e. blockysql.WithErrorTranslation.
+	DBOptions []blockysql.Option
+
+	// ErrorMappings add or override the entries of the DefaultErrorMappings.
+	// They are checked in order, before the default ones.
+	ErrorMappings driver.ErrorMappings
+}
+
+// OpenDB opens a new database connection for the given modernc.org/sqlite
+// data source name and returns a *blockysql.DB wrapper.
+// An in-memory database exists only within a single connection,
+// thus the connection pool of such database is limited to one connection.
+func OpenDB(ctx context.Context, dsn string, opts Options) (*blockysql.DB, error) {
+	db := sql.OpenDB(&connector{
+		dsn:    dsn,
+		driver: ocsql.Wrap(sqliteDriver(), opts.TraceOpts...),
+	})
+	if strings.Contains(dsn, memoryPath) || strings.Contains(dsn, "mode=memory") {
+		db.SetMaxOpenConns(1)
+	}
+
+	d := &DB{db: db, errorMappings: defaultErrorMappings.With(opts.ErrorMappings...)}
+	bdb, err := blockysql.NewDB(d, opts.DBOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if the database could be opened.
+	row := db.QueryRowContext(ctx, "SELECT sqlite_version()")
+	if err = row.Scan(&d.serverInfo.Version); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("sqliteblockysql: open database failed: %w", bdb.TranslateError(err))
+	}
+	d.serverInfo.ServerVersion, _ = driver.ParseServerVersion(d.serverInfo.Version)
+	return bdb, nil
+}
+
+// sqliteDriver returns the driver registered by the modernc.org/sqlite package.
+// The package doesn't export its instance, thus it is taken from the database/sql registry,
+// so that the functions registered with sqlite.RegisterFunction are available.
+func sqliteDriver() sqldriver.Driver {
+	db, _ := sql.Open("sqlite", "")
+	defer db.Close()
+	return db.Driver()
+}
+
+// connector is a database/sql/driver.Connector with a fixed data source name.
+type connector struct {
+	dsn    string
+	driver sqldriver.Driver
+}
+
+// Connect implements database/sql/driver.Connector.
+func (c *connector) Connect(context.Context) (sqldriver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+// Driver implements database/sql/driver.Connector.
+func (c *connector) Driver() sqldriver.Driver {
+	return c.driver
+}
+
+// Compile time check if DB implements driver.DB and the optional driver interfaces.
+var (
+	_ driver.DB                  = (*DB)(nil)
+	_ driver.ErrorDetailer       = (*DB)(nil)
+	_ driver.ServerInformer      = (*DB)(nil)
+	_ driver.CapabilityDescriber = (*DB)(nil)
+)
+
+// DB is the driver for the SQLite database.
+type DB struct {
+	db            *sql.DB
+	serverInfo    driver.ServerInfo
+	errorMappings driver.ErrorMappings
+}
+
+// DriverName implements driver.DB
+func (d *DB) DriverName() string {
+	return "sqlite"
+}
+
+// Dialect implements driver.DB
+func (d *DB) Dialect() string {
+	return driver.DialectSQLite
+}
+
+// ServerInfo implements driver.ServerInformer.
+func (d *DB) ServerInfo() driver.ServerInfo {
+	return d.serverInfo
+}
+
+// Capabilities implements driver.CapabilityDescriber.
+func (d *DB) Capabilities() driver.Capabilities {
+	return driver.DefaultCapabilities(driver.DialectSQLite, d.serverInfo)
+}
+
+// ErrorCode implements driver.DB.
+// The error is matched first by its extended result code,
+// and then by its primary result code.
+func (d *DB) ErrorCode(err error) bserr.Code {
+	if code, ok := driver.SentinelErrorCode(err); ok {
+		return code
+	}
+
+	details, ok := d.ErrorDetails(err)
+	if !ok {
+		return bserr.Unknown
+	}
+
+	if code, ok := d.errorMappings.Code(details); ok {
+		return code
+	}
+
+	details.Number &= 0xff
+	if code, ok := d.errorMappings.Code(details); ok {
+		return code
+	}
+	return bserr.Unknown
+}
+
+// HasErrorDetails returns true if the driver supports error details,
+// such as column, table and constraint name.
+// The SQLite errors don't carry the names as separate fields,
+// thus they are extracted from the error message.
+func (d *DB) HasErrorDetails() bool {
+	return true
+}
+
+// ErrorColumn returns the column name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (d *DB) ErrorColumn(err error) string {
+	details, _ := d.ErrorDetails(err)
+	return details.Column
+}
+
+// ErrorTable returns the table name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (d *DB) ErrorTable(err error) string {
+	details, _ := d.ErrorDetails(err)
+	return details.Table
+}
+
+// ErrorConstraint returns the constraint name of the given error
+// if the driver doesn't support it, it should return an empty string.
+// Only the check constraint errors carry the constraint name.
+func (d *DB) ErrorConstraint(err error) string {
+	details, _ := d.ErrorDetails(err)
+	return details.Constraint
+}
+
+// ErrorDetails implements driver.ErrorDetailer.
+// SQLite provides only the extended result code and the message,
+// the table, column and constraint names are extracted from the message.
+func (d *DB) ErrorDetails(err error) (driver.ErrorDetails, bool) {
+	var serr *sqlite.Error
+	if !errors.As(err, &serr) {
+		return driver.ErrorDetails{}, false
+	}
+	md := parseErrorMessage(serr.Code()&0xff, serr.Error())
+	return driver.ErrorDetails{
+		Number:     serr.Code(),
+		Message:    serr.Error(),
+		Table:      md.table,
+		Column:     md.column,
+		Constraint: md.constraint,
+	}, true
+}
+
+// DB returns the underlying database/sql.DB.
+func (d *DB) DB() *sql.DB {
+	return d.db
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqliteblockysql
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/blockysource/blockysql"
+)
+
+func TestDSNFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "sqlite::memory:", want: "file::memory:?_pragma=foreign_keys%281%29"},
+		{url: "sqlite://:memory:", want: "file::memory:?_pragma=foreign_keys%281%29"},
+		{url: "sqlite://:memory:?cache=shared", want: "file::memory:?_pragma=foreign_keys%281%29&cache=shared"},
+		{url: "sqlite:///var/lib/app.db?foreign_keys=0", want: "file:/var/lib/app.db?_pragma=foreign_keys%280%29"},
+		{url: "sqlite://data/app.db", want: "file:data/app.db?_pragma=foreign_keys%281%29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := new(URLOpener).ParseDBURL(tt.url)
+			if err != nil {
+				t.Fatalf("ParseDBURL() error = %v", err)
+			}
+			got, err := dsnFromURL(u)
+			if err != nil {
+				t.Fatalf("dsnFromURL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("dsnFromURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := dsnFromURL(&url.URL{Scheme: SchemaName}); err == nil {
+		t.Error("dsnFromURL() empty path error = nil, want error")
+	}
+}
+
+func TestOpenMemoryURL(t *testing.T) {
+	for _, urlstr := range []string{"sqlite::memory:", "sqlite://:memory:"} {
+		t.Run(urlstr, func(t *testing.T) {
+			db, err := blockysql.OpenDB(context.Background(), urlstr)
+			if err != nil {
+				t.Fatalf("OpenDB() error = %v", err)
+			}
+			defer db.Close()
+		})
+	}
+}
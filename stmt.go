@@ -0,0 +1,109 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Stmt is a prepared statement. It is a wrapper over the database/sql.Stmt,
+// that translates the errors if the DB is configured to do so.
+type Stmt struct {
+	db   *DB
+	stmt *sql.Stmt
+}
+
+// Exec executes a prepared statement with the given arguments and
+// returns a sql.Result summarizing the effect of the statement.
+func (s *Stmt) Exec(args ...any) (sql.Result, error) {
+	res, err := s.stmt.Exec(args...)
+	return res, s.db.wrapErr(err)
+}
+
+// ExecContext executes a prepared statement with the given arguments and
+// returns a sql.Result summarizing the effect of the statement.
+func (s *Stmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	res, err := s.stmt.ExecContext(ctx, args...)
+	return res, s.db.wrapErr(err)
+}
+
+// Query executes a prepared query statement with the given arguments
+// and returns the query results as a *Rows.
+func (s *Stmt) Query(args ...any) (*Rows, error) {
+	rows, err := s.stmt.Query(args...)
+	if err != nil {
+		return nil, s.db.wrapErr(err)
+	}
+	return &Rows{db: s.db, rows: rows}, nil
+}
+
+// QueryContext executes a prepared query statement with the given arguments
+// and returns the query results as a *Rows.
+func (s *Stmt) QueryContext(ctx context.Context, args ...any) (*Rows, error) {
+	rows, err := s.stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, s.db.wrapErr(err)
+	}
+	return &Rows{db: s.db, rows: rows}, nil
+}
+
+// QueryRow executes a prepared query statement with the given arguments.
+// QueryRow always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called.
+func (s *Stmt) QueryRow(args ...any) *Row {
+	return &Row{db: s.db, row: s.stmt.QueryRow(args...)}
+}
+
+// QueryRowContext executes a prepared query statement with the given arguments.
+// QueryRowContext always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called.
+func (s *Stmt) QueryRowContext(ctx context.Context, args ...any) *Row {
+	return &Row{db: s.db, row: s.stmt.QueryRowContext(ctx, args...)}
+}
+
+// Close closes the statement.
+func (s *Stmt) Close() error {
+	return s.db.wrapErr(s.stmt.Close())
+}
+
+// Stmt returns the underlying database/sql.Stmt.
+func (s *Stmt) Stmt() *sql.Stmt {
+	return s.stmt
+}
+
+// WrapStmt returns the Stmt, that translates the errors of the given statement,
+// if the DB is configured with the WithErrorTranslation.
+// It returns nil if the statement is nil, i.e. when the preparation failed.
+func (d *DB) WrapStmt(stmt *sql.Stmt) *Stmt {
+	if stmt == nil {
+		return nil
+	}
+	return &Stmt{db: d, stmt: stmt}
+}
+
+// WrapStmt returns the Stmt, that translates the errors of the given statement,
+// if the DB is configured with the WithErrorTranslation.
+// It returns nil if the statement is nil, i.e. when the preparation failed.
+func (t *Tx) WrapStmt(stmt *sql.Stmt) *Stmt {
+	return t.db.WrapStmt(stmt)
+}
+
+// WrapStmt returns the Stmt, that translates the errors of the given statement,
+// if the DB is configured with the WithErrorTranslation.
+// It returns nil if the statement is nil, i.e. when the preparation failed.
+func (c *Conn) WrapStmt(stmt *sql.Stmt) *Stmt {
+	return c.db.WrapStmt(stmt)
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 The Blocky Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/blockysource/blockysql/bserr"
+	"github.com/blockysource/blockysql/driver"
+)
+
+// Tx is a driver specific wrapper over the database/sql.Tx.
+// It provides the same error inspection methods as the DB
+// that started the transaction.
+type Tx struct {
+	db *DB
+	tx *sql.Tx
+}
+
+// DriverName returns the name of the driver.
+func (t *Tx) DriverName() string {
+	return t.db.DriverName()
+}
+
+// Dialect returns the dialect of the database connection.
+func (t *Tx) Dialect() string {
+	return t.db.Dialect()
+}
+
+// Capabilities returns the features supported by the connected database server.
+func (t *Tx) Capabilities() driver.Capabilities {
+	return t.db.Capabilities()
+}
+
+// ErrorCode returns the error code of the given error
+// if the driver supports it.
+func (t *Tx) ErrorCode(err error) bserr.Code {
+	return t.db.ErrorCode(err)
+}
+
+// HasErrorDetails returns true if the driver supports error details,
+// such as column, table and constraint name.
+func (t *Tx) HasErrorDetails() bool {
+	return t.db.HasErrorDetails()
+}
+
+// ErrorColumn returns the column name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (t *Tx) ErrorColumn(err error) string {
+	return t.db.ErrorColumn(err)
+}
+
+// ErrorTable returns the table name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (t *Tx) ErrorTable(err error) string {
+	return t.db.ErrorTable(err)
+}
+
+// ErrorConstraint returns the constraint name of the given error
+// if the driver doesn't support it, it should return an empty string.
+func (t *Tx) ErrorConstraint(err error) string {
+	return t.db.ErrorConstraint(err)
+}
+
+// ErrorClass returns the classification of the given error.
+func (t *Tx) ErrorClass(err error) bserr.Class {
+	return t.db.ErrorClass(err)
+}
+
+// ErrorDetails returns the details of the given error.
+// It returns false if the err is nil or the details are not available.
+func (t *Tx) ErrorDetails(err error) (driver.ErrorDetails, bool) {
+	return t.db.ErrorDetails(err)
+}
+
+// TranslateError translates the given driver error into the *bserr.Error,
+// that carries its code and details, and wraps the original error.
+func (t *Tx) TranslateError(err error) error {
+	return t.db.TranslateError(err)
+}
+
+// Tx returns the underlying database/sql.Tx.
+func (t *Tx) Tx() *sql.Tx {
+	return t.tx
+}
+
+// Commit commits the transaction.
+func (t *Tx) Commit() error {
+	return t.db.wrapErr(t.tx.Commit())
+}
+
+// Rollback aborts the transaction.
+func (t *Tx) Rollback() error {
+	return t.db.wrapErr(t.tx.Rollback())
+}
+
+// Exec executes a query that doesn't return rows.
+// The args are for any placeholder parameters in the query.
+func (t *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	res, err := t.tx.Exec(t.db.rebindQuery(query), args...)
+	return res, t.db.wrapErr(err)
+}
+
+// ExecContext executes a query that doesn't return rows.
+// The args are for any placeholder parameters in the query.
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := t.tx.ExecContext(ctx, t.db.rebindQuery(query), args...)
+	return res, t.db.wrapErr(err)
+}
+
+// Prepare creates a prepared statement for use within a transaction.
+// The returned statement operates within the transaction and will be closed
+// when the transaction has been committed or rolled back.
+func (t *Tx) Prepare(query string) (*sql.Stmt, error) {
+	stmt, err := t.tx.Prepare(t.db.rebindQuery(query))
+	return stmt, t.db.wrapErr(err)
+}
+
+// PrepareContext creates a prepared statement for use within a transaction.
+// The returned statement operates within the transaction and will be closed
+// when the transaction has been committed or rolled back.
+func (t *Tx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	stmt, err := t.tx.PrepareContext(ctx, t.db.rebindQuery(query))
+	return stmt, t.db.wrapErr(err)
+}
+
+// Query executes a query that returns rows, typically a SELECT.
+// The args are for any placeholder parameters in the query.
+func (t *Tx) Query(query string, args ...any) (*sql.Rows, error) {
+	rows, err := t.tx.Query(t.db.rebindQuery(query), args...)
+	return rows, t.db.wrapErr(err)
+}
+
+// QueryContext executes a query that returns rows, typically a SELECT.
+// The args are for any placeholder parameters in the query.
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := t.tx.QueryContext(ctx, t.db.rebindQuery(query), args...)
+	return rows, t.db.wrapErr(err)
+}
+
+// QueryRow executes a query that is expected to return at most one row.
+// QueryRow always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called, use the WrapRow to translate them.
+func (t *Tx) QueryRow(query string, args ...any) *sql.Row {
+	return t.tx.QueryRow(t.db.rebindQuery(query), args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row.
+// QueryRowContext always returns a non-nil value. Errors are deferred until
+// Row's Scan method is called, use the WrapRow to translate them.
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.tx.QueryRowContext(ctx, t.db.rebindQuery(query), args...)
+}
+
+// Stmt returns a transaction-specific prepared statement from
+// an existing statement.
+func (t *Tx) Stmt(stmt *sql.Stmt) *sql.Stmt {
+	return t.tx.Stmt(stmt)
+}
+
+// StmtContext returns a transaction-specific prepared statement from
+// an existing statement.
+func (t *Tx) StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	return t.tx.StmtContext(ctx, stmt)
+}